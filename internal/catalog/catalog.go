@@ -0,0 +1,136 @@
+// Package catalog lists the dependencies the wizard offers, grouped by category
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+//go:embed catalog.json
+var embedded []byte
+
+// UserFile is the path, relative to os.UserConfigDir, of the file that overrides the embedded catalog
+const UserFile = "go-scaffold/catalog.json"
+
+// Entry is a dependency of the catalog
+type Entry struct {
+	Module      string `json:"module"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Category    string `json:"category"`
+	// Version is the recommended version, go get chooses when empty
+	Version string `json:"version"`
+	// Conflicts are the modules that should not be used together with this one
+	Conflicts []string `json:"conflicts,omitempty"`
+}
+
+// Catalog keeps the entries in the order they were declared
+type Catalog struct {
+	Entries []Entry
+}
+
+// Load returns the embedded catalog, overridden by the user file when there is one:
+// user entries replace the embedded entry with the same module, new ones are added at the end
+func Load() (*Catalog, error) {
+	c, err := parse(embedded)
+	if err != nil {
+		return nil, fmt.Errorf("parsing embedded catalog: %w", err)
+	}
+
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return c, nil
+	}
+	name := filepath.Join(dir, filepath.FromSlash(UserFile))
+	b, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	user, err := parse(b)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	c.merge(user)
+	return c, nil
+}
+
+func parse(b []byte) (*Catalog, error) {
+	var entries []Entry
+	if err := json.Unmarshal(b, &entries); err != nil {
+		return nil, err
+	}
+	return &Catalog{Entries: entries}, nil
+}
+
+func (c *Catalog) merge(other *Catalog) {
+	for _, entry := range other.Entries {
+		if i := c.index(entry.Module); i >= 0 {
+			c.Entries[i] = entry
+		} else {
+			c.Entries = append(c.Entries, entry)
+		}
+	}
+}
+
+func (c *Catalog) index(module string) int {
+	for i, entry := range c.Entries {
+		if entry.Module == module {
+			return i
+		}
+	}
+	return -1
+}
+
+// Lookup returns the entry of module
+func (c *Catalog) Lookup(module string) (Entry, bool) {
+	if i := c.index(module); i >= 0 {
+		return c.Entries[i], true
+	}
+	return Entry{}, false
+}
+
+// Grouped returns the entries sorted by category, categories are kept in the order they first appear
+func (c *Catalog) Grouped() []Entry {
+	var categories []string
+	byCategory := map[string][]Entry{}
+	for _, entry := range c.Entries {
+		if _, ok := byCategory[entry.Category]; !ok {
+			categories = append(categories, entry.Category)
+		}
+		byCategory[entry.Category] = append(byCategory[entry.Category], entry)
+	}
+
+	grouped := make([]Entry, 0, len(c.Entries))
+	for _, category := range categories {
+		grouped = append(grouped, byCategory[category]...)
+	}
+	return grouped
+}
+
+// CheckConflicts returns an error naming the first two modules that conflict with each other
+func (c *Catalog) CheckConflicts(modules []string) error {
+	selected := map[string]struct{}{}
+	for _, module := range modules {
+		selected[module] = struct{}{}
+	}
+	for _, module := range modules {
+		entry, ok := c.Lookup(module)
+		if !ok {
+			continue
+		}
+		for _, conflict := range entry.Conflicts {
+			if _, ok := selected[conflict]; ok {
+				return fmt.Errorf("%s conflicts with %s, choose only one of them", module, conflict)
+			}
+		}
+	}
+	return nil
+}
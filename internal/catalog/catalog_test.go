@@ -0,0 +1,122 @@
+package catalog
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+var (
+	// a stricter subset of the module paths go accepts, see https://go.dev/ref/mod#go-mod-file-ident
+	pathElement   = regexp.MustCompile(`^[a-z0-9][a-z0-9._~-]*$`)
+	semver        = regexp.MustCompile(`^v(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)\.(0|[1-9][0-9]*)(-[0-9A-Za-z.-]+)?$`)
+	majorSuffix   = regexp.MustCompile(`/v([2-9]|[1-9][0-9]+)$`)
+	hostedDomains = map[string]int{"github.com": 3, "gitlab.com": 3, "bitbucket.org": 3}
+)
+
+func checkModulePath(module string) string {
+	elems := strings.Split(module, "/")
+	if !strings.Contains(elems[0], ".") {
+		return "the first path element must be a domain"
+	}
+	for _, elem := range elems {
+		if !pathElement.MatchString(elem) || strings.HasSuffix(elem, ".") {
+			return "invalid path element " + elem
+		}
+	}
+	if n, ok := hostedDomains[elems[0]]; ok && len(elems) < n {
+		return elems[0] + " modules need an owner and a repository"
+	}
+	return ""
+}
+
+func TestEmbeddedCatalog(t *testing.T) {
+	c, err := parse(embedded)
+	if err != nil {
+		t.Fatalf("parsing embedded catalog: %v", err)
+	}
+	if len(c.Entries) == 0 {
+		t.Fatal("the embedded catalog is empty")
+	}
+
+	seen := map[string]struct{}{}
+	for _, entry := range c.Entries {
+		if problem := checkModulePath(entry.Module); problem != "" {
+			t.Errorf("%s: %s", entry.Module, problem)
+		}
+		if _, ok := seen[entry.Module]; ok {
+			t.Errorf("%s: listed twice", entry.Module)
+		}
+		seen[entry.Module] = struct{}{}
+
+		if entry.Name == "" || entry.Description == "" || entry.Category == "" {
+			t.Errorf("%s: name, description and category are required", entry.Module)
+		}
+		if entry.Version != "" {
+			if !semver.MatchString(entry.Version) {
+				t.Errorf("%s: version %s is not a semantic version", entry.Module, entry.Version)
+			}
+			major := "v0"
+			if m := majorSuffix.FindStringSubmatch(entry.Module); m != nil {
+				major = "v" + m[1]
+			}
+			if got := strings.SplitN(entry.Version, ".", 2)[0]; got != major && !(major == "v0" && got == "v1") {
+				t.Errorf("%s: version %s does not match the major version of the module path", entry.Module, entry.Version)
+			}
+		}
+	}
+
+	for _, entry := range c.Entries {
+		for _, conflict := range entry.Conflicts {
+			if _, ok := seen[conflict]; !ok {
+				t.Errorf("%s: conflicts with %s, which is not in the catalog", entry.Module, conflict)
+			}
+		}
+	}
+}
+
+func TestCheckModulePath(t *testing.T) {
+	tests := []struct {
+		module string
+		valid  bool
+	}{
+		{module: "github.com/stretchr/testify", valid: true},
+		{module: "go.uber.org/mock", valid: true},
+		{module: "github.com/redis/go-redis/v9", valid: true},
+		{module: "github/com/stretchr/testify"},
+		{module: "github.com/stretchr"},
+		{module: "github.com/Stretchr/testify"},
+		{module: "net/http"},
+	}
+	for _, tt := range tests {
+		if got := checkModulePath(tt.module) == ""; got != tt.valid {
+			t.Errorf("%s: expected valid to be %t, got %t", tt.module, tt.valid, got)
+		}
+	}
+}
+
+func TestMergeAndConflicts(t *testing.T) {
+	c, err := parse(embedded)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.merge(&Catalog{Entries: []Entry{
+		{Module: "github.com/spf13/cobra", Name: "cobra", Description: "pinned", Category: "cli", Version: "v1.8.0"},
+		{Module: "example.com/internal/lib", Name: "lib", Description: "in house", Category: "internal"},
+	}})
+
+	cobra, ok := c.Lookup("github.com/spf13/cobra")
+	if !ok || cobra.Version != "v1.8.0" {
+		t.Errorf("expected the user entry to replace cobra, got %+v", cobra)
+	}
+	if _, ok := c.Lookup("example.com/internal/lib"); !ok {
+		t.Error("expected the user entry to be added")
+	}
+
+	if err := c.CheckConflicts([]string{"github.com/spf13/viper", "github.com/ilyakaznacheev/cleanenv"}); err == nil {
+		t.Error("expected viper and cleanenv to conflict")
+	}
+	if err := c.CheckConflicts([]string{"github.com/spf13/viper", "github.com/spf13/cobra"}); err != nil {
+		t.Errorf("unexpected conflict: %v", err)
+	}
+}
@@ -0,0 +1,62 @@
+// Package fsys abstracts the file system generated files are written to
+package fsys
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// Writer creates the files of a generated project, paths are slash separated and relative to the project root
+type Writer interface {
+	MkdirAll(name string, perm fs.FileMode) error
+	Create(name string) (io.WriteCloser, error)
+}
+
+type osWriter struct {
+	root string
+}
+
+// OS returns a Writer that writes to the directory root of the OS file system
+func OS(root string) Writer {
+	return &osWriter{root: root}
+}
+
+func (w *osWriter) MkdirAll(name string, perm fs.FileMode) error {
+	return os.MkdirAll(w.path(name), perm)
+}
+
+func (w *osWriter) Create(name string) (io.WriteCloser, error) {
+	return os.Create(w.path(name))
+}
+
+func (w *osWriter) path(name string) string {
+	return filepath.Join(w.root, filepath.FromSlash(name))
+}
+
+// Mem is an in memory Writer, it maps the path of every created file to its content
+type Mem map[string][]byte
+
+func (m Mem) MkdirAll(string, fs.FileMode) error {
+	return nil
+}
+
+func (m Mem) Create(name string) (io.WriteCloser, error) {
+	name = path.Clean(name)
+	m[name] = nil
+	return &memFile{mem: m, name: name}, nil
+}
+
+type memFile struct {
+	bytes.Buffer
+	mem  Mem
+	name string
+}
+
+func (f *memFile) Close() error {
+	f.mem[f.name] = f.Bytes()
+	return nil
+}
@@ -18,20 +18,28 @@ func (s Selection) Value() string {
 
 type choiceModel struct {
 	choices    []string
+	groups     []string
 	cursor     int
 	selections []Selection
 	header     string
+	err        error
 	next       func() (tea.Model, tea.Cmd)
 	onEnter    func(selected []Selection) error
+	validate   func(selected []Selection) error
 }
 
 type ChoiceModelOptions struct {
 	Choices []string
 	// the value you wish to receive for every choice, in the same order
-	Values  []string
+	Values []string
+	// the group of every choice, in the same order, a heading is shown before the first choice of each group.
+	// Leave empty to show no headings
+	Groups  []string
 	Header  string
 	Next    func() (tea.Model, tea.Cmd)
 	OnEnter func(selected []Selection) error
+	// Validate, when set, is called before OnEnter, the error is shown and the selection can be changed
+	Validate func(selected []Selection) error
 }
 
 func NewchoiceModel(opts ChoiceModelOptions) choiceModel {
@@ -41,17 +49,22 @@ func NewchoiceModel(opts ChoiceModelOptions) choiceModel {
 	if len(opts.Values) != len(opts.Choices) {
 		panic("values and choices must be the same length")
 	}
+	if len(opts.Groups) != 0 && len(opts.Groups) != len(opts.Choices) {
+		panic("groups and choices must be the same length")
+	}
 	selected := make([]Selection, len(opts.Values))
 	for i, value := range opts.Values {
 		selected[i] = Selection{false, value}
 	}
 	return choiceModel{
 		choices:    opts.Choices,
+		groups:     opts.Groups,
 		cursor:     0,
 		selections: selected,
 		header:     opts.Header,
 		next:       opts.Next,
 		onEnter:    opts.OnEnter,
+		validate:   opts.Validate,
 	}
 }
 func (m choiceModel) Init() tea.Cmd {
@@ -75,6 +88,12 @@ func (choice choiceModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case " ":
 			choice.selections[choice.cursor].IsSelected = !choice.selections[choice.cursor].IsSelected
 		case "enter":
+			if choice.validate != nil {
+				choice.err = choice.validate(choice.selections)
+				if choice.err != nil {
+					return choice, nil
+				}
+			}
 			if err := choice.onEnter(choice.selections); err != nil {
 				log.Fatal(err)
 			}
@@ -91,6 +110,9 @@ func (m choiceModel) View() string {
 	}
 	print += "\n\n"
 	for i, choice := range m.choices {
+		if len(m.groups) > 0 && (i == 0 || m.groups[i] != m.groups[i-1]) {
+			print += m.groups[i] + "\n"
+		}
 		cursor := " "
 		if i == m.cursor {
 			cursor = ">"
@@ -103,6 +125,9 @@ func (m choiceModel) View() string {
 
 		print += fmt.Sprintf("%s [%s] %s\n", cursor, checked, choice)
 	}
+	if m.err != nil {
+		print += "\n" + m.err.Error() + "\n"
+	}
 	print += HelpStyle("\n(press space to select, enter to continue, q to quit)\n")
 
 	return print
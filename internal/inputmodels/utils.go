@@ -5,3 +5,7 @@ import "github.com/charmbracelet/lipgloss"
 func HelpStyle(str string) string {
 	return lipgloss.NewStyle().Foreground(lipgloss.Color("#626262")).Render(str)
 }
+
+func ErrorStyle(str string) string {
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F5F")).Render(str)
+}
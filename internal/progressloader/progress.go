@@ -1,9 +1,11 @@
 package progressloader
 
 import (
+	"errors"
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/charmbracelet/bubbles/progress"
@@ -16,24 +18,63 @@ import (
 const (
 	maxWidth = 120
 	padding  = 4
+	// failedLines is how much of the output of a failed command the failure view shows
+	failedLines = 15
 )
 
+// Status keeps the last event reported while generating a project, Update can be called
+// from another goroutine while the loader shows it
+type Status struct {
+	mu   sync.Mutex
+	last project.Event
+}
+
+func (s *Status) Update(e project.Event) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.last = e
+}
+
+func (s *Status) get() project.Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.last
+}
+
 type loader struct {
-	conf     *project.Configuration
+	status   *Status
+	generate func() error
 	progress progress.Model
 	once     bool
+	done     bool
+	err      error
 }
 type tickMsg time.Time
 
-func NewLoader(conf *project.Configuration) tea.Model {
+// doneMsg is sent once generate returns
+type doneMsg struct {
+	err error
+}
+
+// NewLoader shows the progress of generate, which is run by the program and reports to status.
+// Once generate fails, the loader shows why and waits for a key, Err returns the error afterwards
+func NewLoader(status *Status, generate func() error) tea.Model {
 	return &loader{
-		conf:     conf,
+		status:   status,
+		generate: generate,
 		progress: progress.New(progress.WithDefaultGradient()),
 	}
 }
 
+// Err returns the error generate returned
+func (m loader) Err() error {
+	return m.err
+}
+
 func (l loader) Init() tea.Cmd {
-	return tickCmd()
+	return tea.Batch(tickCmd(), func() tea.Msg {
+		return doneMsg{err: l.generate()}
+	})
 }
 
 func (m loader) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -47,8 +88,17 @@ func (m loader) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case doneMsg:
+		m.done = true
+		m.err = msg.err
+		return m, nil
+
 	case tickMsg:
-		if m.progress.Percent() == 1.0 {
+		if m.err != nil {
+			// the failure view stays until a key is pressed
+			return m, nil
+		}
+		if m.done && m.progress.Percent() == 1.0 {
 			if !m.once {
 				m.once = true
 			} else {
@@ -70,9 +120,38 @@ func (m loader) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m loader) View() string {
+	last := m.status.get()
+	if m.err != nil {
+		return "\n" + last.Status + "\n" + failure(m.err, last.Log) + inputmodels.HelpStyle("Press any key to quit")
+	}
 	pad := strings.Repeat(" ", padding)
-	return "\n" + m.conf.GetCurrentCmd() + "\n" + pad + m.progress.View() + pad +
-		"\n\n" + inputmodels.HelpStyle("Press any key to quit")
+	view := "\n" + last.Status + "\n" + pad + m.progress.View() + pad + "\n\n"
+	if m.done && last.Log != "" {
+		view += "Every command and its output is logged in " + last.Log + "\n\n"
+	}
+	return view + inputmodels.HelpStyle("Press any key to quit")
+}
+
+// failure tells which step failed and why, with the end of the output of the failed command
+func failure(err error, logPath string) string {
+	var b strings.Builder
+	var stepErr *project.StepError
+	if errors.As(err, &stepErr) {
+		b.WriteString(inputmodels.ErrorStyle("✗ "+stepErr.Step+" failed, "+stepErr.Class+" error") + "\n")
+		err = stepErr.Err
+	} else {
+		b.WriteString(inputmodels.ErrorStyle("✗ The project could not be generated") + "\n")
+	}
+	b.WriteString(err.Error() + "\n")
+	var cmdErr *project.CommandError
+	if errors.As(err, &cmdErr) && len(cmdErr.Output) > 0 {
+		b.WriteString("\n" + cmdErr.Tail(failedLines) + "\n")
+	}
+	b.WriteString("\n")
+	if logPath != "" {
+		b.WriteString("Every command and its output is logged in " + logPath + "\n\n")
+	}
+	return b.String()
 }
 
 func tickCmd() tea.Cmd {
@@ -82,5 +161,8 @@ func tickCmd() tea.Cmd {
 }
 
 func (l loader) calculatePercent() float64 {
-	return l.conf.CalculateProgress()
+	if l.done {
+		return 1
+	}
+	return l.status.get().Progress
 }
@@ -0,0 +1,59 @@
+package project
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+// goVersionRunner answers go list -m -f {{.GoVersion}} with version
+type goVersionRunner struct {
+	recordingRunner
+	version string
+}
+
+func (r *goVersionRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	r.recordingRunner.Run(ctx, cmd)
+	if cmd.String() == "go list -m -f {{.GoVersion}}" {
+		return []byte(r.version), nil
+	}
+	return nil, nil
+}
+
+func TestStartReadsGoVersionForCI(t *testing.T) {
+	tests := []struct {
+		name    string
+		ci      string
+		version string
+		want    string
+		reads   bool
+	}{
+		{name: "github", ci: CIGitHub, version: "1.26.0\n", want: "1.26.0", reads: true},
+		{name: "unreadable go.mod", ci: CIGitLab, version: "", want: "", reads: true},
+		{name: "no CI", ci: CINone, version: "1.26.0\n", want: "", reads: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			runner := &goVersionRunner{version: tt.version}
+			c := NewConfiguration("example")
+			c.FS = fsys.Mem{}
+			c.Runner = runner
+			c.CI = tt.ci
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if c.GoVersion != tt.want {
+				t.Errorf("expected GoVersion %q, got %q", tt.want, c.GoVersion)
+			}
+			reads := false
+			for _, cmd := range runner.cmds {
+				reads = reads || cmd.String() == "go list -m -f {{.GoVersion}}"
+			}
+			if reads != tt.reads {
+				t.Errorf("expected go.mod to be read: %v, got %v", tt.reads, runner.cmds)
+			}
+		})
+	}
+}
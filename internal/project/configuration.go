@@ -1,12 +1,19 @@
 package project
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
-	"os/exec"
 	"path"
+	"sort"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/muesli/termenv"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
 )
 
 var (
@@ -23,16 +30,53 @@ type template interface {
 }
 
 type Configuration struct {
-	Name           string
-	WebLibrary     string
-	DoVendor       bool
-	Dependencies   map[string]struct{}
+	Name       string
+	WebLibrary string
+	DoVendor   bool
+	// Dependencies maps every module to add to the version or query go get resolves it with,
+	// e.g. v1.9.1, latest or upgrade. An empty version lets go get choose
+	Dependencies   map[string]string
 	DBProvider     string
 	DBLibrary      string
+	Mocks          string
+	Git            string
+	Tasks          string
+	Lint           bool
+	CI             string
+	Deploy         string
+	Logging        string
+	Telemetry      bool
+	Auth           bool
+	mu             sync.Mutex
 	processedDeps  int
+	depStatuses    []DependencyStatus
+	installing     bool
+	resolved       map[string]string
+	cmdLog         []commandRecord
 	vendorFinished bool
 	currentCmd     string
 	Template       template
+	// Root is the directory the project is generated in, "." by default
+	Root string
+	// FS is where the project files are written, the OS file system rooted at Root when nil
+	FS fsys.Writer
+	// Runner runs go commands in Root, OSRunner when nil
+	Runner CommandRunner
+	// OnEvent, when set, is called as Start makes progress
+	OnEvent func(Event)
+	// Offline makes go commands use the module cache only, Start fails up front when selected modules
+	// are missing from it, unless SkipMissing is set, in which case they are left out
+	Offline     bool
+	SkipMissing bool
+	// DownloadAll downloads the dependencies of the dependencies once they are installed, go vet and go test
+	// need them too, so the module cache has everything the project needs offline
+	DownloadAll bool
+	// PreHooks run once the folders of the project exist, before go mod init,
+	// PostHooks once the project was generated
+	PreHooks  []Hook
+	PostHooks []Hook
+	// GoVersion is the go directive of go.mod, it's only read when a CI pipeline is generated
+	GoVersion string
 }
 
 func NewConfiguration(strpath string) *Configuration {
@@ -43,60 +87,242 @@ func NewConfiguration(strpath string) *Configuration {
 	return &Configuration{
 		Name:         name,
 		DoVendor:     false,
-		Dependencies: map[string]struct{}{},
+		Dependencies: map[string]string{},
+		Mocks:        MocksInline,
+		Git:          GitNone,
+		Tasks:        TasksNone,
+		CI:           CINone,
+		Deploy:       DeployNone,
+		Logging:      LoggingStd,
+		Root:         ".",
+	}
+}
+
+// FileWriter returns the Writer project files are written to
+func (c *Configuration) FileWriter() fsys.Writer {
+	if c.FS == nil {
+		c.FS = fsys.OS(c.Root)
+	}
+	return eventWriter{Writer: c.FS, c: c}
+}
+
+// CommandRunner returns the runner go commands are run with
+func (c *Configuration) CommandRunner() CommandRunner {
+	if c.Runner == nil {
+		c.Runner = OSRunner{}
+	}
+	return c.Runner
+}
+
+// run runs the command name with args in Root
+func (c *Configuration) run(ctx context.Context, name string, args ...string) ([]byte, error) {
+	return c.runIn(ctx, c.Root, name, args...)
+}
+
+// runIn runs the command name with args in dir, the working directory when empty
+func (c *Configuration) runIn(ctx context.Context, dir, name string, args ...string) ([]byte, error) {
+	cmd := Command{Dir: dir, Name: name, Args: args}
+	if c.Offline {
+		cmd.Env = offlineEnv
+	}
+	start := time.Now()
+	out, err := c.CommandRunner().Run(ctx, cmd)
+	err = newCommandError(cmd, out, err)
+	r := commandRecord{cmd: cmd, start: start, duration: time.Since(start), out: out}
+	var cmdErr *CommandError
+	if errors.As(err, &cmdErr) {
+		r.exitCode = cmdErr.ExitCode
+	}
+	c.record(r)
+	c.emit(Event{Kind: EventCommandFinished, Command: cmd, Output: out, Err: err})
+	return out, err
+}
+
+// UsesTestify reports whether testify was selected, in which case generated tests use it for assertions
+func (c *Configuration) UsesTestify() bool {
+	_, ok := c.Dependencies[DependencyTestify]
+	return ok
+}
+
+// GeneratesMocks reports whether an internal/helloworld/mocks package is generated,
+// otherwise tests declare their own inline fakes
+func (c *Configuration) GeneratesMocks() bool {
+	return c.Mocks == MocksFakes || c.Mocks == MocksGomock
+}
+
+// StructuredLogging reports whether the generated code logs through pkg/logging instead of the standard log package,
+// it's only generated for web projects
+func (c *Configuration) StructuredLogging() bool {
+	return c.WebLibrary != WebLibraryNone && c.Logging != LoggingStd && c.Logging != ""
+}
+
+// InstrumentsTelemetry reports whether the server is instrumented with OpenTelemetry,
+// only full projects have a server to instrument
+func (c *Configuration) InstrumentsTelemetry() bool {
+	return c.Telemetry && c.WebLibrary != WebLibraryNone && c.DBLibrary != DBLibraryNone
+}
+
+// GeneratesAuth reports whether pkg/auth and the JWT and API key middlewares are generated,
+// only web projects have requests to authenticate
+func (c *Configuration) GeneratesAuth() bool {
+	return c.Auth && c.WebLibrary != WebLibraryNone
+}
+
+// AppName is the last element of the module name as a DNS label, so it can name Kubernetes objects and images
+func (c *Configuration) AppName() string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(path.Base(c.Name)) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			b.WriteRune(r)
+		} else {
+			b.WriteRune('-')
+		}
+	}
+	name := b.String()
+	if len(name) > 63 {
+		name = name[:63]
 	}
+	name = strings.Trim(name, "-")
+	if name == "" {
+		return "app"
+	}
+	return name
+}
+
+// InitsGit reports whether a git repository is created for the project, along with its .gitignore and .gitattributes
+func (c *Configuration) InitsGit() bool {
+	return c.Git == GitInit || c.Git == GitCommit
 }
 
+// UsesGomock reports whether mocks are generated by mockgen (go.uber.org/mock)
+func (c *Configuration) UsesGomock() bool {
+	return c.Mocks == MocksGomock
+}
+
+// GetCurrentCmd returns the report of what was done so far, including the live status of every
+// dependency while they are installed
 func (c *Configuration) GetCurrentCmd() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.installing {
+		return c.currentCmd + c.dependencyReport()
+	}
 	return c.currentCmd
 }
 
+// CalculateProgress returns how far Start got, from 0 to 1, it's safe to call while Start runs
 func (c *Configuration) CalculateProgress() float64 {
-	// i dont care about data races
-	if !c.DoVendor {
-		return float64(c.processedDeps) / float64(len(c.Dependencies))
-	} else {
-		if !c.vendorFinished {
-			return float64(c.processedDeps) / (float64(len(c.Dependencies)) + 1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	done, total := c.processedDeps, len(c.Dependencies)
+	if c.DoVendor {
+		total++
+		if c.vendorFinished {
+			done = total
 		}
+	}
+	if total == 0 {
 		return 1
 	}
+	return float64(done) / float64(total)
 }
 
-func (c *Configuration) Start() (err error) {
-	c.currentCmd = "Creating folders...\n\n"
-	err = c.createFolders()
-	if err != nil {
-		return
+// appendStatus adds s to the report of what was done so far
+func (c *Configuration) appendStatus(s string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.currentCmd = c.currentCmd + s
+}
+
+// Start generates the project, when it fails the error is a *StepError
+func (c *Configuration) Start(ctx context.Context) (err error) {
+	var logPath string
+	defer func() {
+		c.emit(Event{Kind: EventDone, Err: err, Versions: c.ResolvedVersions(), Log: logPath})
+	}()
+
+	// a Configuration can be started again, nothing of the previous run is carried over
+	c.mu.Lock()
+	c.currentCmd = ""
+	c.cmdLog = nil
+	c.processedDeps = 0
+	c.depStatuses = nil
+	c.resolved = nil
+	c.vendorFinished = false
+	c.mu.Unlock()
+	if c.Offline {
+		step := "Checking the module cache"
+		c.startStep(step)
+		if err = c.checkModuleCache(ctx); err != nil {
+			return newStepError(step, FailureToolchain, err)
+		}
 	}
-	c.currentCmd = "Initializing mod...\n\n"
-	err = c.modInit()
-	if err != nil {
-		return
+	// from here on the project exists, the log tells what happened to it
+	defer c.writeLog(&err)
+	logPath = c.LogPath()
+
+	step := "Creating folders"
+	c.startStep(step)
+	if err = c.createFolders(); err != nil {
+		return newStepError(step, FailureFilesystem, err)
 	}
-	c.currentCmd = c.currentCmd + "Installing dependencies...\n\n"
-	err = c.installDependencies()
-	if err != nil {
-		return
+	if err = c.runHooks(ctx, c.PreHooks); err != nil {
+		return err
+	}
+	step = "Initializing mod"
+	c.startStep(step)
+	if err = c.modInit(ctx); err != nil {
+		return newStepError(step, FailureToolchain, err)
+	}
+	step = "Installing dependencies"
+	c.startStep(step)
+	if err = c.installDependencies(ctx); err != nil {
+		return newStepError(step, FailureToolchain, err)
+	}
+	c.resolveVersions(ctx)
+	if c.DownloadAll {
+		step = "Downloading the dependencies of the dependencies"
+		c.startStep(step)
+		if _, err = c.run(ctx, "go", "mod", "download", "all"); err != nil {
+			return newStepError(step, FailureToolchain, err)
+		}
+	}
+	if c.CI != CINone {
+		c.readGoVersion(ctx)
 	}
 	if c.Template != nil {
-		c.currentCmd = c.currentCmd + "Creating helper methods and middlewares in pkg/...\n\n"
-		err = c.Template.Build(c)
-		if err != nil {
-			return
+		step = "Creating helper methods and middlewares in pkg/"
+		c.startStep(step)
+		if err = c.Template.Build(c); err != nil {
+			return newStepError(step, FailureTemplate, err)
 		}
 	}
 
-	c.runGoFmt()
+	c.runGoFmt(ctx)
+
+	if err = c.saveSettings(); err != nil {
+		return newStepError("Saving settings", FailureFilesystem, err)
+	}
 
 	if c.DoVendor {
-		c.currentCmd = c.currentCmd + "Vendoring...\n\n"
-		err = c.vendor()
-		if err != nil {
-			return
+		step = "Vendoring"
+		c.startStep(step)
+		if err = c.vendor(ctx); err != nil {
+			return newStepError(step, FailureToolchain, err)
 		}
 	}
-	c.currentCmd = c.currentCmd + "Finished!"
+	if err = c.runHooks(ctx, c.PostHooks); err != nil {
+		return err
+	}
+	// once the hooks ran, so the initial commit has what they did
+	if c.InitsGit() {
+		step = "Initializing git repository"
+		c.startStep(step)
+		if err = c.initGit(ctx); err != nil {
+			return newStepError(step, FailureToolchain, err)
+		}
+	}
+	c.appendStatus("Finished!")
 	return nil
 }
 
@@ -110,7 +336,7 @@ func (c *Configuration) createFolders() (err error) {
 		"./docs",
 	}
 	for _, dir := range dirs {
-		err = os.MkdirAll(dir, 0755)
+		err = c.FileWriter().MkdirAll(dir, 0755)
 		if err != nil {
 			return
 		}
@@ -118,62 +344,229 @@ func (c *Configuration) createFolders() (err error) {
 	return nil
 }
 
-func (c *Configuration) modInit() (err error) {
-	err = exec.Command("go", "mod", "init", c.Name).Run()
+func (c *Configuration) modInit(ctx context.Context) (err error) {
+	out, err := c.run(ctx, "go", "mod", "init", c.Name)
 	if err != nil {
+		c.mu.Lock()
 		c.currentCmd = fmt.Sprintf(
 			"Failed to run command: %s\nError: %s",
 			colorFg(fmt.Sprintf("go mod init %s", c.Name), blueFg),
-			colorFg(err.Error(), redFg),
+			colorFg(outputExcerpt(out, err), redFg),
 		)
+		c.mu.Unlock()
 	}
 	return
 }
 
-func (c *Configuration) installDependencies() (err error) {
+func (c *Configuration) installDependencies(ctx context.Context) (err error) {
+	modules := c.selectedDependencies()
+	c.mu.Lock()
 	deps := c.Dependencies
-	if c.WebLibrary != WebLibraryNone {
-		deps[c.WebLibrary] = struct{}{}
+	c.depStatuses = nil
+	args := []string{"get"}
+	for _, module := range modules {
+		if isStdlib(module) {
+			c.processedDeps++
+			continue
+		}
+		c.depStatuses = append(c.depStatuses, DependencyStatus{Module: module, Version: deps[module], State: DependencyPending})
+		args = append(args, FormatDependency(module, deps[module]))
 	}
-	if c.DBLibrary != DBLibraryNone {
-		deps[c.DBLibrary] = struct{}{}
+	c.installing = true
+	c.mu.Unlock()
+
+	defer func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.installing = false
+		if len(deps) > 0 {
+			c.currentCmd = c.currentCmd + c.dependencyReport() + "Finished installing dependencies!\n\n"
+		}
+	}()
+
+	if len(args) == 1 {
+		return nil
 	}
-	if c.DBProvider != DBProviderNone {
-		deps[c.DBProvider] = struct{}{}
+	if _, err = c.run(ctx, "go", args...); err == nil {
+		for i := range args[1:] {
+			c.setDependencyState(i, DependencyOK, "")
+		}
+		return nil
 	}
-	for dep := range deps {
-		startingCmd := c.currentCmd
-		c.currentCmd = startingCmd + fmt.Sprintf("Getting dependency: %s\n", colorFg(dep, blueFg))
-		err = exec.Command("go", "get", dep).Run()
+
+	// a single module that can't be resolved fails the whole batch,
+	// getting them one by one tells which one it was and still adds the others
+	for i, dep := range args[1:] {
+		if err = ctx.Err(); err != nil {
+			return err
+		}
+		out, err := c.run(ctx, "go", "get", dep)
 		if err != nil {
-			//log and continue
-			c.currentCmd = startingCmd + fmt.Sprintf(
-				"Failed to get dependency:%s => %s\n",
-				colorFg(dep, blueFg),
-				colorFg("Check if it exists", redFg),
-			)
+			excerpt := outputExcerpt(out, err)
+			c.setDependencyState(i, DependencyFailed, excerpt)
+			c.emit(Event{Kind: EventDependencyFailed, Dependency: c.depStatuses[i].Module, Output: out, Err: err})
+			continue
 		}
-		c.processedDeps++
-	}
-	if len(deps) > 0 {
-		c.currentCmd = c.currentCmd + "Finished installing dependencies!\n\n"
+		c.setDependencyState(i, DependencyOK, "")
 	}
 	return nil
 }
 
-func (c *Configuration) vendor() (err error) {
-	err = exec.Command("go", "mod", "vendor").Run()
+// selectedDependencies adds the selected libraries to Dependencies, keeping the version they were pinned to,
+// and returns every dependency sorted
+func (c *Configuration) selectedDependencies() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	deps := append([]string{c.WebLibrary, c.DBLibrary, c.DBProvider, c.loggingDependency(), c.authDependency()}, c.telemetryDependencies()...)
+	for _, dep := range append(deps, c.testDependencies()...) {
+		if _, ok := c.Dependencies[dep]; !ok && dep != "" {
+			c.Dependencies[dep] = ""
+		}
+	}
+	modules := make([]string, 0, len(c.Dependencies))
+	for dep := range c.Dependencies {
+		modules = append(modules, dep)
+	}
+	sort.Strings(modules)
+	return modules
+}
+
+// loggingDependency returns the module pkg/logging is built on, empty when it only needs the standard library
+func (c *Configuration) loggingDependency() string {
+	if !c.StructuredLogging() {
+		return ""
+	}
+	switch c.Logging {
+	case LoggingZap:
+		return DependencyZap
+	case LoggingZerolog:
+		return DependencyZerolog
+	}
+	return ""
+}
+
+// authDependency returns the JWT library when the auth middlewares are generated
+func (c *Configuration) authDependency() string {
+	if c.GeneratesAuth() {
+		return DependencyJWT
+	}
+	return ""
+}
+
+// telemetryDependencies returns the OpenTelemetry SDK and exporters, and the instrumentation of the libraries
+// of the project, when it's instrumented
+func (c *Configuration) telemetryDependencies() []string {
+	if !c.InstrumentsTelemetry() {
+		return nil
+	}
+	deps := []string{
+		DependencyOtel,
+		DependencyOtelSdk,
+		DependencyOtelSdkMetric,
+		DependencyOtelOtlpTraceHttp,
+		DependencyOtelOtlpMetricHttp,
+		DependencyOtelStdoutTrace,
+		DependencyOtelStdoutMetric,
+		DependencyOtelPrometheus,
+		DependencyPrometheusClient,
+	}
+	switch c.WebLibrary {
+	case WebLibraryGin:
+		deps = append(deps, DependencyOtelGin)
+	case WebLibraryFiber:
+		deps = append(deps, DependencyOtelFiber)
+	case WebLibraryGorillamux, WebLibraryHttp:
+		deps = append(deps, DependencyOtelHttp)
+	}
+	switch c.DBLibrary {
+	case DBLibraryGorm:
+		deps = append(deps, DependencyOtelGorm)
+	case DBLibrarySql, DBLibrarySqlx:
+		deps = append(deps, DependencyOtelSql)
+	}
+	return deps
+}
+
+// testDependencies returns the drivers needed by the generated repository tests to run
+// against an in-memory SQLite database. Repositories are only generated for full projects,
+// and MySQL queries are not valid SQLite, so those tests need DB_TEST_DSN instead
+func (c *Configuration) testDependencies() []string {
+	var deps []string
+	if c.UsesGomock() {
+		deps = append(deps, DependencyGomock)
+	}
+	if c.WebLibrary == WebLibraryNone {
+		return deps
+	}
+	switch {
+	case c.DBLibrary == DBLibraryGorm:
+		deps = append(deps, TestDriverGormSqlite)
+	case c.DBProvider == DBProviderPostgres:
+		deps = append(deps, TestDriverSqlite)
+	}
+	return deps
+}
+
+func (c *Configuration) vendor(ctx context.Context) (err error) {
+	_, err = c.run(ctx, "go", "mod", "vendor")
 	if err != nil {
 		return
 	}
+	c.mu.Lock()
 	c.vendorFinished = true
+	c.mu.Unlock()
 	return nil
 }
 
+// settings are persisted in the generated project, so later generators (e.g. for new resources)
+// can follow the same choices
+type settings struct {
+	Name       string `json:"name"`
+	WebLibrary string `json:"web_library"`
+	DBLibrary  string `json:"db_library"`
+	DBProvider string `json:"db_provider"`
+	Mocks      string `json:"mocks"`
+}
+
+func (c *Configuration) saveSettings() (err error) {
+	w := c.FileWriter()
+	err = w.MkdirAll(SettingsDir, 0755)
+	if err != nil {
+		return
+	}
+	b, err := json.MarshalIndent(settings{
+		Name:       c.Name,
+		WebLibrary: c.WebLibrary,
+		DBLibrary:  c.DBLibrary,
+		DBProvider: c.DBProvider,
+		Mocks:      c.Mocks,
+	}, "", "  ")
+	if err != nil {
+		return
+	}
+	return writeFile(w, path.Join(SettingsDir, settingsFile), append(b, '\n'))
+}
+
+func writeFile(w fsys.Writer, name string, b []byte) (err error) {
+	f, err := w.Create(name)
+	if err != nil {
+		return
+	}
+	_, err = f.Write(b)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return
+}
+
 func colorFg(val, color string) string {
 	return termenv.String(val).Foreground(term.Color(color)).String()
 }
 
-func (c *Configuration) runGoFmt() {
-	exec.Command("go", "fmt", "./...").Run()
+// runGoFmt doesn't fail the generation, the project is usable unformatted
+func (c *Configuration) runGoFmt(ctx context.Context) {
+	_, err := c.run(ctx, "go", "fmt", "./...")
+	if err != nil {
+		c.appendStatus(fmt.Sprintf("%s failed, see %s\n\n", colorFg("go fmt ./...", redFg), c.LogPath()))
+	}
 }
@@ -0,0 +1,320 @@
+package project
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+type recordingRunner struct {
+	cmds []Command
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd Command) ([]byte, error) {
+	r.cmds = append(r.cmds, cmd)
+	return nil, nil
+}
+
+func TestStartUsesInjectedFSAndRunner(t *testing.T) {
+	files := fsys.Mem{}
+	runner := &recordingRunner{}
+	c := NewConfiguration("example")
+	c.Root = "/projects/example"
+	c.FS = files
+	c.Runner = runner
+	c.WebLibrary = WebLibraryGin
+	c.Dependencies[WebLibraryGin] = "v1.9.1"
+	c.DBLibrary = DBLibraryNone
+	c.DBProvider = DBProviderNone
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"go mod init example",
+		"go get " + WebLibraryGin + "@v1.9.1",
+		"go list -m -f {{.Path}} {{.Version}} all",
+		"go fmt ./...",
+	}
+	if len(runner.cmds) != len(want) {
+		t.Fatalf("expected commands %q, got %v", want, runner.cmds)
+	}
+	for i, cmd := range runner.cmds {
+		if cmd.String() != want[i] {
+			t.Errorf("expected command %q, got %q", want[i], cmd.String())
+		}
+		if cmd.Dir != c.Root {
+			t.Errorf("expected %q to run in %s, got %s", cmd, c.Root, cmd.Dir)
+		}
+	}
+
+	var s settings
+	if err := json.Unmarshal(files[SettingsDir+"/"+settingsFile], &s); err != nil {
+		t.Fatalf("settings were not written: %v", err)
+	}
+	if s.WebLibrary != WebLibraryGin {
+		t.Errorf("expected web library %s in settings, got %s", WebLibraryGin, s.WebLibrary)
+	}
+}
+
+func TestStartAgainResetsProgress(t *testing.T) {
+	c := NewConfiguration("example")
+	c.Runner = &recordingRunner{}
+	c.WebLibrary = WebLibraryGin
+	c.DBLibrary = DBLibraryNone
+	c.DBProvider = DBProviderNone
+
+	for run := 1; run <= 2; run++ {
+		c.FS = fsys.Mem{}
+		if err := c.Start(context.Background()); err != nil {
+			t.Fatalf("run %d: unexpected error: %v", run, err)
+		}
+		if progress := c.CalculateProgress(); progress != 1 {
+			t.Errorf("run %d: expected progress 1, got %v", run, progress)
+		}
+		if statuses := c.DependencyStatuses(); len(statuses) != 1 {
+			t.Errorf("run %d: expected the status of 1 dependency, got %v", run, statuses)
+		}
+	}
+}
+
+func TestResolvedVersionsIsACopy(t *testing.T) {
+	c := NewConfiguration("example")
+	c.resolved = map[string]string{WebLibraryGin: "v1.9.1"}
+
+	c.ResolvedVersions()[WebLibraryGin] = "v0.0.0"
+	if got := c.ResolvedVersions()[WebLibraryGin]; got != "v1.9.1" {
+		t.Errorf("expected the resolved version to stay v1.9.1, got %s", got)
+	}
+}
+
+// failingGetRunner fails every go get that includes module
+type failingGetRunner struct {
+	recordingRunner
+	module string
+}
+
+func (r *failingGetRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	r.recordingRunner.Run(ctx, cmd)
+	if len(cmd.Args) > 0 && cmd.Args[0] == "get" && strings.Contains(cmd.String(), r.module) {
+		return []byte("go: downloading\ngo: module " + r.module + ": not found"), errors.New("exit status 1")
+	}
+	return nil, nil
+}
+
+func TestInstallDependenciesFallsBackToOneByOne(t *testing.T) {
+	runner := &failingGetRunner{module: "example.com/missing"}
+	c := NewConfiguration("example")
+	c.FS = fsys.Mem{}
+	c.Runner = runner
+	c.WebLibrary = WebLibraryHttp
+	c.DBLibrary = DBLibraryNone
+	c.DBProvider = DBProviderNone
+	c.Dependencies["example.com/missing"] = ""
+	c.Dependencies[DependencyTestify] = "v1.11.1"
+
+	var failed []string
+	c.OnEvent = func(e Event) {
+		if e.Kind == EventDependencyFailed {
+			failed = append(failed, e.Dependency)
+		}
+	}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var gets []string
+	for _, cmd := range runner.cmds {
+		if cmd.Args[0] == "get" {
+			gets = append(gets, cmd.String())
+		}
+	}
+	want := []string{
+		"go get example.com/missing " + DependencyTestify + "@v1.11.1",
+		"go get example.com/missing",
+		"go get " + DependencyTestify + "@v1.11.1",
+	}
+	if strings.Join(gets, "\n") != strings.Join(want, "\n") {
+		t.Fatalf("expected commands %q, got %q", want, gets)
+	}
+
+	statuses := c.DependencyStatuses()
+	if len(statuses) != 2 || statuses[0].State != DependencyFailed || statuses[1].State != DependencyOK {
+		t.Fatalf("unexpected statuses: %+v", statuses)
+	}
+	if !strings.Contains(statuses[0].Output, "not found") {
+		t.Errorf("expected the output excerpt in the status, got %q", statuses[0].Output)
+	}
+	if len(failed) != 1 || failed[0] != "example.com/missing" {
+		t.Errorf("expected a dependency_failed event for example.com/missing, got %v", failed)
+	}
+	if progress := c.CalculateProgress(); progress != 1 {
+		t.Errorf("expected progress to be 1, got %f", progress)
+	}
+}
+
+// failingVendorRunner fails go mod vendor
+type failingVendorRunner struct {
+	recordingRunner
+}
+
+func (r *failingVendorRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	r.recordingRunner.Run(ctx, cmd)
+	if cmd.String() == "go mod vendor" {
+		return []byte("go: inconsistent vendoring\nrun go mod tidy\n"), errors.New("exit status 1")
+	}
+	return []byte("ok\n"), nil
+}
+
+func TestStartWritesGenerationLog(t *testing.T) {
+	files := fsys.Mem{}
+	c := NewConfiguration("example")
+	c.FS = files
+	c.Runner = &failingVendorRunner{}
+	c.WebLibrary = WebLibraryNone
+	c.DBLibrary = DBLibraryNone
+	c.DBProvider = DBProviderNone
+	c.DoVendor = true
+
+	var done Event
+	c.OnEvent = func(e Event) {
+		if e.Kind == EventDone {
+			done = e
+		}
+	}
+	err := c.Start(context.Background())
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "Vendoring" || stepErr.Class != FailureToolchain {
+		t.Fatalf("expected vendoring to fail with a toolchain error, got %v", err)
+	}
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.Command.String() != "go mod vendor" {
+		t.Fatalf("expected go mod vendor to fail, got %v", err)
+	}
+	if cmdErr.Tail(1) != "run go mod tidy" {
+		t.Errorf("expected the last line of the output, got %q", cmdErr.Tail(1))
+	}
+	if done.Err != err || done.Log != c.LogPath() {
+		t.Errorf("expected the done event to carry the error and the log path, got %+v", done)
+	}
+
+	log := string(files[SettingsDir+"/"+LogFile])
+	for _, want := range []string{
+		"$ go mod init example\n",
+		"$ go fmt ./...\n",
+		"$ go mod vendor\n",
+		// the error does not come from a process, so there is no exit code
+		"exit code: -1\ngo: inconsistent vendoring\nrun go mod tidy\n",
+	} {
+		if !strings.Contains(log, want) {
+			t.Errorf("expected the log to contain %q, got:\n%s", want, log)
+		}
+	}
+}
+
+func TestAppName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"example", "example"},
+		{"github.com/Acme/User_Service", "user-service"},
+		{"gitlab.com/acme/api.v2", "api-v2"},
+		{"_", "app"},
+		{strings.Repeat("a", 62) + "-b", strings.Repeat("a", 62)},
+	}
+	for _, tt := range tests {
+		c := &Configuration{Name: tt.name}
+		if got := c.AppName(); got != tt.want {
+			t.Errorf("AppName of %q: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestLoggingDependency(t *testing.T) {
+	tests := []struct {
+		webLibrary string
+		logging    string
+		want       string
+	}{
+		{WebLibraryGin, LoggingStd, ""},
+		{WebLibraryGin, LoggingSlog, ""},
+		{WebLibraryFiber, LoggingZap, DependencyZap},
+		{WebLibraryHttp, LoggingZerolog, DependencyZerolog},
+		// pkg/logging is only generated for web projects
+		{WebLibraryNone, LoggingZap, ""},
+	}
+	for _, tt := range tests {
+		c := NewConfiguration("example")
+		c.WebLibrary = tt.webLibrary
+		c.Logging = tt.logging
+
+		deps := c.selectedDependencies()
+		for _, dep := range []string{DependencyZap, DependencyZerolog} {
+			if got := slices.Contains(deps, dep); got != (dep == tt.want) {
+				t.Errorf("%s logging with %q: expected %s selected to be %v, got %v", tt.logging, tt.webLibrary, dep, !got, got)
+			}
+		}
+	}
+}
+
+func TestTelemetryDependencies(t *testing.T) {
+	tests := []struct {
+		webLibrary string
+		dbLibrary  string
+		telemetry  bool
+		want       []string
+	}{
+		{WebLibraryGin, DBLibrarySql, false, nil},
+		{WebLibraryGin, DBLibrarySql, true, []string{DependencyOtelSdk, DependencyOtelGin, DependencyOtelSql}},
+		{WebLibraryFiber, DBLibraryGorm, true, []string{DependencyOtelSdk, DependencyOtelFiber, DependencyOtelGorm}},
+		{WebLibraryGorillamux, DBLibrarySqlx, true, []string{DependencyOtelSdk, DependencyOtelHttp, DependencyOtelSql}},
+		// only full projects have a server and a database to instrument
+		{WebLibraryHttp, DBLibraryNone, true, nil},
+		{WebLibraryNone, DBLibrarySql, true, nil},
+	}
+	for _, tt := range tests {
+		c := NewConfiguration("example")
+		c.WebLibrary = tt.webLibrary
+		c.DBLibrary = tt.dbLibrary
+		c.Telemetry = tt.telemetry
+
+		deps := c.selectedDependencies()
+		for _, dep := range []string{DependencyOtelSdk, DependencyOtelGin, DependencyOtelFiber, DependencyOtelHttp, DependencyOtelSql, DependencyOtelGorm} {
+			want := slices.Contains(tt.want, dep)
+			if got := slices.Contains(deps, dep); got != want {
+				t.Errorf("%s with %s, telemetry %v: expected %s selected to be %v, got %v", tt.webLibrary, tt.dbLibrary, tt.telemetry, dep, want, got)
+			}
+		}
+	}
+}
+
+func TestAuthDependency(t *testing.T) {
+	tests := []struct {
+		webLibrary string
+		auth       bool
+		want       bool
+	}{
+		{WebLibraryGin, false, false},
+		{WebLibraryGin, true, true},
+		{WebLibraryHttp, true, true},
+		// the middlewares are only generated for web projects
+		{WebLibraryNone, true, false},
+	}
+	for _, tt := range tests {
+		c := NewConfiguration("example")
+		c.WebLibrary = tt.webLibrary
+		c.DBLibrary = DBLibrarySql
+		c.Auth = tt.auth
+
+		if got := slices.Contains(c.selectedDependencies(), DependencyJWT); got != tt.want {
+			t.Errorf("%q with auth %v: expected %s selected to be %v, got %v", tt.webLibrary, tt.auth, DependencyJWT, tt.want, got)
+		}
+	}
+}
@@ -22,3 +22,95 @@ const (
 	WebLibraryHttp       = "net/http"
 	WebLibraryNone       = ""
 )
+
+const (
+	DependencyTestify = "github.com/stretchr/testify"
+)
+
+// In-memory database drivers used by the generated repository tests
+const (
+	TestDriverSqlite     = "modernc.org/sqlite"
+	TestDriverGormSqlite = "github.com/glebarez/sqlite"
+)
+
+// How generated tests mock the logic and repository interfaces
+const (
+	MocksInline = "inline"
+	MocksFakes  = "fakes"
+	MocksGomock = "gomock"
+)
+
+const (
+	DependencyGomock = "go.uber.org/mock"
+)
+
+// Whether a git repository is created for the project, with an initial commit or not
+const (
+	GitNone   = "none"
+	GitInit   = "init"
+	GitCommit = "commit"
+)
+
+// Which task runner file is generated, with build, test, lint and the rest of the usual tasks
+const (
+	TasksNone     = "none"
+	TasksMake     = "make"
+	TasksTaskfile = "task"
+)
+
+// Which CI pipeline is generated, it vets, lints and tests the project against the DBMS and builds its image
+const (
+	CINone   = "none"
+	CIGitHub = "github"
+	CIGitLab = "gitlab"
+)
+
+// Which deployment manifests are generated for the image of the Dockerfile
+const (
+	DeployNone      = "none"
+	DeployKustomize = "kustomize"
+	DeployHelm      = "helm"
+)
+
+// Which library web projects log with, the standard log package keeps the generated code as it is,
+// the others log structured entries through pkg/logging, with a request logger and request IDs
+const (
+	LoggingStd     = "log"
+	LoggingSlog    = "slog"
+	LoggingZap     = "zap"
+	LoggingZerolog = "zerolog"
+)
+
+const (
+	DependencyZap     = "go.uber.org/zap"
+	DependencyZerolog = "github.com/rs/zerolog"
+)
+
+// OpenTelemetry modules, the SDK and its exporters, along with the instrumentation of every web and DB library
+const (
+	DependencyOtel               = "go.opentelemetry.io/otel"
+	DependencyOtelSdk            = "go.opentelemetry.io/otel/sdk"
+	DependencyOtelSdkMetric      = "go.opentelemetry.io/otel/sdk/metric"
+	DependencyOtelOtlpTraceHttp  = "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	DependencyOtelOtlpMetricHttp = "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	DependencyOtelStdoutTrace    = "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	DependencyOtelStdoutMetric   = "go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	DependencyOtelPrometheus     = "go.opentelemetry.io/otel/exporters/prometheus"
+	DependencyPrometheusClient   = "github.com/prometheus/client_golang"
+	DependencyOtelGin            = "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	DependencyOtelFiber          = "github.com/gofiber/contrib/otelfiber/v2"
+	DependencyOtelHttp           = "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	DependencyOtelSql            = "github.com/XSAM/otelsql"
+	DependencyOtelGorm           = "gorm.io/plugin/opentelemetry"
+)
+
+// DependencyJWT parses and verifies the tokens of the auth middlewares
+const DependencyJWT = "github.com/golang-jwt/jwt/v5"
+
+// SettingsDir holds the files go-scaffold keeps inside the generated project
+const (
+	SettingsDir  = ".go-scaffold"
+	settingsFile = "settings.json"
+	// LogFile records every command run while generating the project, with its output
+	LogFile = "generate.log"
+)
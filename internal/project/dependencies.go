@@ -0,0 +1,168 @@
+package project
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"sort"
+	"strings"
+)
+
+// ParseDependency splits a dependency written as module[@version] into its module and version,
+// e.g. github.com/gin-gonic/gin@v1.9.1, github.com/gin-gonic/gin@latest or github.com/gin-gonic/gin
+func ParseDependency(dep string) (module, version string) {
+	module, version, _ = strings.Cut(strings.TrimSpace(dep), "@")
+	return module, version
+}
+
+// FormatDependency returns the argument go get takes for module at version
+func FormatDependency(module, version string) string {
+	version = strings.TrimPrefix(version, "@")
+	if version == "" {
+		return module
+	}
+	return module + "@" + version
+}
+
+// isStdlib reports whether dep is a package of the standard library, like net/http or database/sql,
+// those can't be added with go get
+func isStdlib(dep string) bool {
+	first, _, _ := strings.Cut(dep, "/")
+	return !strings.Contains(first, ".")
+}
+
+// ResolvedVersions maps every dependency to the version go get resolved it to,
+// dependencies that failed to install are not there. The map is a copy, callers may keep and change it
+func (c *Configuration) ResolvedVersions() map[string]string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return maps.Clone(c.resolved)
+}
+
+// resolveVersions looks up the version of the module providing every dependency,
+// dependencies can be packages of a module, like github.com/mongodb/mongo-go-driver/mongo
+func (c *Configuration) resolveVersions(ctx context.Context) {
+	out, err := c.run(ctx, "go", "list", "-m", "-f", "{{.Path}} {{.Version}}", "all")
+	if err != nil {
+		return
+	}
+	modules := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		path, version, ok := strings.Cut(scanner.Text(), " ")
+		if ok && version != "" {
+			modules[path] = version
+		}
+	}
+
+	resolved := map[string]string{}
+	for dep := range c.Dependencies {
+		for module := dep; module != "." && module != ""; module = parentPath(module) {
+			if version, ok := modules[module]; ok {
+				resolved[dep] = version
+				break
+			}
+		}
+	}
+	c.mu.Lock()
+	c.resolved = resolved
+	c.mu.Unlock()
+	if len(resolved) == 0 {
+		return
+	}
+
+	deps := make([]string, 0, len(resolved))
+	for dep := range resolved {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	report := "Resolved versions:\n"
+	for _, dep := range deps {
+		report = report + fmt.Sprintf("  %s %s\n", colorFg(dep, blueFg), resolved[dep])
+	}
+	c.appendStatus(report + "\n")
+}
+
+// readGoVersion sets GoVersion to the go directive of go.mod, the CI pipeline tests with it. It's left as is
+// when go.mod can't be read, the pipeline then only tests with the latest release
+func (c *Configuration) readGoVersion(ctx context.Context) {
+	out, err := c.run(ctx, "go", "list", "-m", "-f", "{{.GoVersion}}")
+	if version := strings.TrimSpace(string(out)); err == nil && version != "" {
+		c.GoVersion = version
+	}
+}
+
+// DependencyState tells how far the installation of a dependency got
+type DependencyState string
+
+const (
+	DependencyPending DependencyState = "pending"
+	DependencyOK      DependencyState = "ok"
+	DependencyFailed  DependencyState = "failed"
+)
+
+// DependencyStatus is the status of a dependency added by go get
+type DependencyStatus struct {
+	Module  string
+	Version string
+	State   DependencyState
+	// Output is an excerpt of what go get printed when it failed
+	Output string
+}
+
+// DependencyStatuses returns the status of every dependency go get adds, it's safe to call while Start runs
+func (c *Configuration) DependencyStatuses() []DependencyStatus {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]DependencyStatus(nil), c.depStatuses...)
+}
+
+func (c *Configuration) setDependencyState(i int, state DependencyState, output string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.depStatuses[i].State = state
+	c.depStatuses[i].Output = output
+	c.processedDeps++
+}
+
+// dependencyReport shows the status of every dependency, c.mu must be held
+func (c *Configuration) dependencyReport() string {
+	var report strings.Builder
+	for _, status := range c.depStatuses {
+		dep := FormatDependency(status.Module, status.Version)
+		switch status.State {
+		case DependencyOK:
+			fmt.Fprintf(&report, "  %s %s\n", colorFg("ok", blueFg), dep)
+		case DependencyFailed:
+			fmt.Fprintf(&report, "  %s %s => %s\n", colorFg("failed", redFg), dep, colorFg(status.Output, redFg))
+		default:
+			fmt.Fprintf(&report, "  %s %s\n", "pending", dep)
+		}
+	}
+	return report.String()
+}
+
+// outputExcerptLines is how many of the last lines printed by a failed command are kept
+const outputExcerptLines = 3
+
+// outputExcerpt returns the last lines of out, or err when the command printed nothing
+func outputExcerpt(out []byte, err error) string {
+	lines := strings.Split(strings.TrimSpace(string(out)), "\n")
+	if len(lines) == 1 && lines[0] == "" {
+		return err.Error()
+	}
+	if len(lines) > outputExcerptLines {
+		lines = lines[len(lines)-outputExcerptLines:]
+	}
+	return strings.Join(lines, "\n    ")
+}
+
+func parentPath(p string) string {
+	i := strings.LastIndex(p, "/")
+	if i < 0 {
+		return ""
+	}
+	return p[:i]
+}
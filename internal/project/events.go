@@ -0,0 +1,154 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+// EventKind tells what happened while generating a project
+type EventKind string
+
+const (
+	EventStepStarted      EventKind = "step_started"
+	EventFileWritten      EventKind = "file_written"
+	EventCommandFinished  EventKind = "command_finished"
+	EventDependencyFailed EventKind = "dependency_failed"
+	EventDone             EventKind = "done"
+)
+
+// Event reports the progress of Start, only the fields relevant to its Kind are set
+type Event struct {
+	Kind EventKind
+	// Step describes the step that started
+	Step string
+	// Path is the slash separated path of the written file, relative to the project root
+	Path string
+	// Command is the command that finished, Output its combined stdout and stderr
+	Command Command
+	Output  []byte
+	// Dependency is the module that could not be added
+	Dependency string
+	// Versions maps every dependency to the version it resolved to, once done
+	Versions map[string]string
+	// Log is the path of the generation log, once done
+	Log string
+	// Err is the error of a failed command or dependency, or the one Start returned
+	Err error
+	// Progress goes from 0 to 1, as reported by CalculateProgress
+	Progress float64
+	// Status is the report shown by the terminal UI so far
+	Status string
+}
+
+// jsonEvent is how an Event is written as JSON, Status is left out since it's meant for a terminal
+type jsonEvent struct {
+	Kind       EventKind         `json:"kind"`
+	Step       string            `json:"step,omitempty"`
+	Path       string            `json:"path,omitempty"`
+	Command    *Command          `json:"command,omitempty"`
+	ExitCode   *int              `json:"exit_code,omitempty"`
+	Output     string            `json:"output,omitempty"`
+	Dependency string            `json:"dependency,omitempty"`
+	Versions   map[string]string `json:"versions,omitempty"`
+	Log        string            `json:"log,omitempty"`
+	Error      string            `json:"error,omitempty"`
+	Failure    string            `json:"failure,omitempty"`
+	Progress   float64           `json:"progress"`
+}
+
+// MarshalJSON writes the fields relevant to the Kind of the event, command_finished events
+// have the exit code of the command, -1 when it could not be started or was killed, and failed done events
+// the step that failed and the class of the failure
+func (e Event) MarshalJSON() ([]byte, error) {
+	j := jsonEvent{
+		Kind:       e.Kind,
+		Step:       e.Step,
+		Path:       e.Path,
+		Output:     string(e.Output),
+		Dependency: e.Dependency,
+		Versions:   e.Versions,
+		Log:        e.Log,
+		Progress:   e.Progress,
+	}
+	if e.Kind == EventCommandFinished {
+		cmd := e.Command
+		exitCode := 0
+		var cmdErr *CommandError
+		if errors.As(e.Err, &cmdErr) {
+			exitCode = cmdErr.ExitCode
+		} else if e.Err != nil {
+			exitCode = -1
+		}
+		j.Command, j.ExitCode = &cmd, &exitCode
+	}
+	if e.Err != nil {
+		j.Error = e.Err.Error()
+	}
+	var stepErr *StepError
+	if e.Kind == EventDone && errors.As(e.Err, &stepErr) {
+		j.Step, j.Failure = stepErr.Step, stepErr.Class
+	}
+	return json.Marshal(j)
+}
+
+func (c *Configuration) emit(e Event) {
+	if c.OnEvent == nil {
+		return
+	}
+	e.Progress = c.CalculateProgress()
+	e.Status = c.GetCurrentCmd()
+	c.OnEvent(e)
+}
+
+// startStep adds step to the status and reports it
+func (c *Configuration) startStep(step string) {
+	c.appendStatus(step + "...\n\n")
+	c.emit(Event{Kind: EventStepStarted, Step: step})
+}
+
+// eventWriter reports every file created through Writer
+type eventWriter struct {
+	fsys.Writer
+	c *Configuration
+}
+
+// MkdirAll and Create return *fs.PathError, whatever the Writer returns, so failures to write
+// the project are told apart from the rest
+func (w eventWriter) MkdirAll(name string, perm fs.FileMode) error {
+	return pathError("mkdir", name, w.Writer.MkdirAll(name, perm))
+}
+
+func (w eventWriter) Create(name string) (io.WriteCloser, error) {
+	f, err := w.Writer.Create(name)
+	if err != nil {
+		return nil, pathError("create", name, err)
+	}
+	w.c.emit(Event{Kind: EventFileWritten, Path: name})
+	return pathFile{WriteCloser: f, name: name}, nil
+}
+
+type pathFile struct {
+	io.WriteCloser
+	name string
+}
+
+func (f pathFile) Write(b []byte) (int, error) {
+	n, err := f.WriteCloser.Write(b)
+	return n, pathError("write", f.name, err)
+}
+
+func (f pathFile) Close() error {
+	return pathError("close", f.name, f.WriteCloser.Close())
+}
+
+func pathError(op, name string, err error) error {
+	var pathErr *fs.PathError
+	if err == nil || errors.As(err, &pathErr) {
+		return err
+	}
+	return &fs.PathError{Op: op, Path: name, Err: err}
+}
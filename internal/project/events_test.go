@@ -0,0 +1,48 @@
+package project
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEventJSON(t *testing.T) {
+	cmd := Command{Dir: "example", Name: "go", Args: []string{"get", "example.com/missing"}}
+	tests := []struct {
+		event Event
+		want  string
+	}{
+		{
+			event: Event{Kind: EventStepStarted, Step: "Creating folders", Status: "Creating folders...\n\n"},
+			want:  `{"kind":"step_started","step":"Creating folders","progress":0}`,
+		},
+		{
+			event: Event{Kind: EventCommandFinished, Command: cmd, Output: []byte("ok\n"), Progress: 0.5},
+			want: `{"kind":"command_finished","command":{"dir":"example","name":"go","args":["get","example.com/missing"]},` +
+				`"exit_code":0,"output":"ok\n","progress":0.5}`,
+		},
+		{
+			event: Event{Kind: EventCommandFinished, Command: cmd, Output: []byte("not found\n"),
+				Err: &CommandError{Command: cmd, ExitCode: 1, Err: errors.New("exit status 1")}},
+			want: `{"kind":"command_finished","command":{"dir":"example","name":"go","args":["get","example.com/missing"]},` +
+				`"exit_code":1,"output":"not found\n","error":"go get example.com/missing: exit status 1","progress":0}`,
+		},
+		{
+			event: Event{Kind: EventDone, Versions: map[string]string{"example.com/dep": "v1.0.0"}, Log: "example/.go-scaffold/generate.log", Progress: 1},
+			want:  `{"kind":"done","versions":{"example.com/dep":"v1.0.0"},"log":"example/.go-scaffold/generate.log","progress":1}`,
+		},
+		{
+			event: Event{Kind: EventDone, Err: &StepError{Step: "Vendoring", Class: FailureToolchain, Err: errors.New("exit status 1")}},
+			want:  `{"kind":"done","step":"Vendoring","error":"Vendoring: exit status 1","failure":"toolchain","progress":0}`,
+		},
+	}
+	for _, tt := range tests {
+		b, err := json.Marshal(tt.event)
+		if err != nil {
+			t.Fatalf("marshaling %s: %v", tt.event.Kind, err)
+		}
+		if string(b) != tt.want {
+			t.Errorf("expected\n%s\ngot\n%s", tt.want, b)
+		}
+	}
+}
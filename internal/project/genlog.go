@@ -0,0 +1,146 @@
+package project
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// CommandError is returned when a command run while generating a project fails
+type CommandError struct {
+	Command Command
+	// ExitCode is -1 when the command could not be started or was killed
+	ExitCode int
+	// Output is the combined stdout and stderr of the command
+	Output []byte
+	Err    error
+}
+
+func (e *CommandError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Command, e.Err)
+}
+
+func (e *CommandError) Unwrap() error {
+	return e.Err
+}
+
+// Tail returns the last n lines of the output
+func (e *CommandError) Tail(n int) string {
+	lines := strings.Split(strings.TrimRight(string(e.Output), "\n"), "\n")
+	if len(lines) > n {
+		lines = lines[len(lines)-n:]
+	}
+	return strings.Join(lines, "\n")
+}
+
+// commandRecord is an entry of the generation log
+type commandRecord struct {
+	cmd      Command
+	start    time.Time
+	duration time.Duration
+	exitCode int
+	out      []byte
+}
+
+// newCommandError returns nil when err is nil
+func newCommandError(cmd Command, out []byte, err error) error {
+	if err == nil {
+		return nil
+	}
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &CommandError{Command: cmd, ExitCode: exitCode, Output: out, Err: err}
+}
+
+func (c *Configuration) record(r commandRecord) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cmdLog = append(c.cmdLog, r)
+}
+
+// LogPath returns the path of the generation log of the project
+func (c *Configuration) LogPath() string {
+	return filepath.Join(c.Root, SettingsDir, LogFile)
+}
+
+// writeLog writes every command run so far to the generation log, its error is only returned
+// when generating the project did not fail already
+func (c *Configuration) writeLog(err *error) {
+	c.mu.Lock()
+	var b strings.Builder
+	for _, r := range c.cmdLog {
+		fmt.Fprintf(&b, "$ %s\n", r.cmd)
+		if r.cmd.Dir != "" {
+			fmt.Fprintf(&b, "# dir: %s\n", r.cmd.Dir)
+		}
+		if len(r.cmd.Env) > 0 {
+			fmt.Fprintf(&b, "# env: %s\n", strings.Join(r.cmd.Env, " "))
+		}
+		fmt.Fprintf(&b, "# started: %s, took: %s, exit code: %d\n",
+			r.start.Format(time.RFC3339), r.duration.Round(time.Millisecond), r.exitCode)
+		b.Write(r.out)
+		if len(r.out) > 0 && r.out[len(r.out)-1] != '\n' {
+			b.WriteByte('\n')
+		}
+		b.WriteByte('\n')
+	}
+	c.mu.Unlock()
+
+	w := c.FileWriter()
+	logErr := w.MkdirAll(SettingsDir, 0755)
+	if logErr == nil {
+		logErr = writeFile(w, path.Join(SettingsDir, LogFile), []byte(b.String()))
+	}
+	if logErr != nil && *err == nil {
+		*err = newStepError("Writing the generation log", FailureFilesystem, logErr)
+	}
+}
+
+// Classes of the failures of Start, they tell what to look at to fix it
+const (
+	// FailureTemplate is a template that could not be parsed or rendered
+	FailureTemplate = "template"
+	// FailureFilesystem is a file or folder of the project that could not be written
+	FailureFilesystem = "filesystem"
+	// FailureToolchain is a go or git command that failed, or a module that is not in the cache in offline mode
+	FailureToolchain = "toolchain"
+	// FailureHook is a pre or post generation hook that failed or timed out
+	FailureHook = "hook"
+)
+
+// StepError is returned by Start, it tells which step failed and the class of the failure
+type StepError struct {
+	Step  string
+	Class string
+	Err   error
+}
+
+func (e *StepError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Step, e.Err)
+}
+
+func (e *StepError) Unwrap() error {
+	return e.Err
+}
+
+// newStepError returns a StepError of class, unless what failed makes it clear that it's of another one
+func newStepError(step, class string, err error) *StepError {
+	var cmdErr *CommandError
+	var missing *MissingModulesError
+	var pathErr *fs.PathError
+	switch {
+	case errors.As(err, &cmdErr), errors.As(err, &missing):
+		class = FailureToolchain
+	case errors.As(err, &pathErr):
+		class = FailureFilesystem
+	}
+	return &StepError{Step: step, Class: class, Err: err}
+}
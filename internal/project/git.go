@@ -0,0 +1,34 @@
+package project
+
+import (
+	"context"
+	"strings"
+)
+
+// initGit creates the repository of the project, with an initial commit authored from the git config of the
+// user when Git is GitCommit. Without git, or without a user in its config, what can't be done is skipped
+func (c *Configuration) initGit(ctx context.Context) error {
+	if _, err := c.run(ctx, "git", "--version"); err != nil {
+		c.appendStatus(colorFg("git is not installed", redFg) + ", the project is not a git repository\n\n")
+		return nil
+	}
+	if _, err := c.run(ctx, "git", "init"); err != nil {
+		return err
+	}
+	if c.Git != GitCommit {
+		return nil
+	}
+
+	for _, key := range []string{"user.name", "user.email"} {
+		out, err := c.run(ctx, "git", "config", key)
+		if err != nil || strings.TrimSpace(string(out)) == "" {
+			c.appendStatus(colorFg(key, redFg) + " is not set in your git config, skipping the initial commit\n\n")
+			return nil
+		}
+	}
+	if _, err := c.run(ctx, "git", "add", "--all"); err != nil {
+		return err
+	}
+	_, err := c.run(ctx, "git", "commit", "--quiet", "--message", "Initial commit, generated by go-scaffold")
+	return err
+}
@@ -0,0 +1,98 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+// gitRunner answers git config with config, and fails every git command when git is not installed
+type gitRunner struct {
+	recordingRunner
+	installed bool
+	config    map[string]string
+}
+
+func (r *gitRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	r.recordingRunner.Run(ctx, cmd)
+	if cmd.Name != "git" {
+		return nil, nil
+	}
+	if !r.installed {
+		return nil, errors.New(`exec: "git": executable file not found in $PATH`)
+	}
+	if len(cmd.Args) == 2 && cmd.Args[0] == "config" {
+		return []byte(r.config[cmd.Args[1]]), nil
+	}
+	return nil, nil
+}
+
+func TestInitGit(t *testing.T) {
+	user := map[string]string{"user.name": "Gopher\n", "user.email": "gopher@example.com\n"}
+	tests := []struct {
+		name   string
+		git    string
+		runner *gitRunner
+		want   []string
+		status string
+	}{
+		{
+			name:   "init",
+			git:    GitInit,
+			runner: &gitRunner{installed: true, config: user},
+			want:   []string{"git --version", "git init"},
+		},
+		{
+			name:   "commit",
+			git:    GitCommit,
+			runner: &gitRunner{installed: true, config: user},
+			want: []string{"git --version", "git init", "git config user.name", "git config user.email",
+				"git add --all", "git commit --quiet --message Initial commit, generated by go-scaffold"},
+		},
+		{
+			name:   "commit without a user",
+			git:    GitCommit,
+			runner: &gitRunner{installed: true, config: map[string]string{"user.name": "Gopher"}},
+			want:   []string{"git --version", "git init", "git config user.name", "git config user.email"},
+			status: "skipping the initial commit",
+		},
+		{
+			name:   "git not installed",
+			git:    GitCommit,
+			runner: &gitRunner{},
+			want:   []string{"git --version"},
+			status: "git is not installed",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := fsys.Mem{}
+			c := NewConfiguration("example")
+			c.FS = files
+			c.Runner = tt.runner
+			c.WebLibrary = WebLibraryNone
+			c.DBLibrary = DBLibraryNone
+			c.DBProvider = DBProviderNone
+			c.Git = tt.git
+
+			if err := c.Start(context.Background()); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, cmd := range tt.runner.cmds {
+				if cmd.Name == "git" {
+					got = append(got, cmd.String())
+				}
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected git commands %q, got %q", tt.want, got)
+			}
+			if !strings.Contains(c.GetCurrentCmd(), tt.status) {
+				t.Errorf("expected %q in the status, got %q", tt.status, c.GetCurrentCmd())
+			}
+		})
+	}
+}
@@ -0,0 +1,77 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	texttemplate "text/template"
+	"time"
+)
+
+// DefaultHookTimeout is how long a hook can run when its Timeout is not set
+const DefaultHookTimeout = 2 * time.Minute
+
+// hookOutputLines is how many of the last lines printed by a hook are shown in the status
+const hookOutputLines = 10
+
+// Hook is a shell script run with sh -c in the project directory, before the project is generated
+// (once its folders exist) or after it was generated. Run is rendered with text/template, with the
+// Configuration as data, so it can use {{.Name}}, {{.WebLibrary}} and so on
+type Hook struct {
+	// Name is shown in the status, the script itself when empty
+	Name    string
+	Run     string
+	Timeout time.Duration
+}
+
+func (h Hook) name() string {
+	if h.Name != "" {
+		return h.Name
+	}
+	return strings.SplitN(strings.TrimSpace(h.Run), "\n", 2)[0]
+}
+
+// runHooks runs hooks in order, the first one that fails stops the rest
+func (c *Configuration) runHooks(ctx context.Context, hooks []Hook) error {
+	for _, hook := range hooks {
+		step := "Running hook " + hook.name()
+		c.startStep(step)
+		// a hook failing is a hook failure, even when what failed is a go command it ran
+		if err := c.runHook(ctx, hook); err != nil {
+			return &StepError{Step: step, Class: FailureHook, Err: err}
+		}
+	}
+	return nil
+}
+
+func (c *Configuration) runHook(ctx context.Context, hook Hook) error {
+	tmpl, err := texttemplate.New(hook.name()).Parse(hook.Run)
+	if err != nil {
+		return err
+	}
+	var script strings.Builder
+	if err := tmpl.Execute(&script, c); err != nil {
+		return err
+	}
+
+	timeout := hook.Timeout
+	if timeout <= 0 {
+		timeout = DefaultHookTimeout
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	out, err := c.run(ctx, "sh", "-c", script.String())
+	if lines := strings.TrimSpace(string(out)); lines != "" {
+		all := strings.Split(lines, "\n")
+		if len(all) > hookOutputLines {
+			all = all[len(all)-hookOutputLines:]
+		}
+		c.appendStatus("    " + strings.Join(all, "\n    ") + "\n\n")
+	}
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return fmt.Errorf("timed out after %s: %w", timeout, err)
+	}
+	return err
+}
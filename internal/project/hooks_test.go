@@ -0,0 +1,81 @@
+package project
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+func TestHooksRunAroundGeneration(t *testing.T) {
+	runner := &recordingRunner{}
+	c := NewConfiguration("example")
+	c.FS = fsys.Mem{}
+	c.Runner = runner
+	c.WebLibrary = WebLibraryNone
+	c.DBLibrary = DBLibraryNone
+	c.DBProvider = DBProviderNone
+	c.PreHooks = []Hook{{Name: "announce", Run: "echo generating {{.Name}}"}}
+	c.PostHooks = []Hook{{Run: "go mod tidy"}, {Run: "git init\ngit add ."}}
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []string{
+		"sh -c echo generating example",
+		"go mod init example",
+		"go list -m -f {{.Path}} {{.Version}} all",
+		"go fmt ./...",
+		"sh -c go mod tidy",
+		"sh -c git init\ngit add .",
+	}
+	var got []string
+	for _, cmd := range runner.cmds {
+		got = append(got, cmd.String())
+	}
+	if strings.Join(got, "|") != strings.Join(want, "|") {
+		t.Fatalf("expected commands %q, got %q", want, got)
+	}
+	for _, step := range []string{"Running hook announce", "Running hook go mod tidy", "Running hook git init"} {
+		if !strings.Contains(c.GetCurrentCmd(), step) {
+			t.Errorf("expected %q in the status, got %q", step, c.GetCurrentCmd())
+		}
+	}
+}
+
+// blockingRunner runs until the context is done
+type blockingRunner struct{}
+
+func (blockingRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	if cmd.Name != "sh" {
+		return nil, nil
+	}
+	<-ctx.Done()
+	return []byte("still running\n"), errors.New("signal: killed")
+}
+
+func TestHookTimeout(t *testing.T) {
+	c := NewConfiguration("example")
+	c.FS = fsys.Mem{}
+	c.Runner = blockingRunner{}
+	c.WebLibrary = WebLibraryNone
+	c.DBLibrary = DBLibraryNone
+	c.DBProvider = DBProviderNone
+	c.PostHooks = []Hook{{Name: "setup", Run: "make setup", Timeout: 10 * time.Millisecond}}
+
+	err := c.Start(context.Background())
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Class != FailureHook || stepErr.Step != "Running hook setup" {
+		t.Fatalf("expected the setup hook to fail, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "timed out after 10ms") {
+		t.Errorf("expected the timeout in the error, got %v", err)
+	}
+	if !strings.Contains(c.GetCurrentCmd(), "still running") {
+		t.Errorf("expected the output of the hook in the status, got %q", c.GetCurrentCmd())
+	}
+}
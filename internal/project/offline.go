@@ -0,0 +1,209 @@
+package project
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// offlineEnv makes go commands use the local module cache only
+var offlineEnv = []string{"GOFLAGS=-mod=mod", "GOPROXY=off"}
+
+// MissingModulesError is returned by Start in offline mode when selected modules are not in the module cache
+type MissingModulesError struct {
+	Modules []string
+}
+
+func (e *MissingModulesError) Error() string {
+	return fmt.Sprintf("not in the module cache, warm it up with go-scaffold warm or run without --offline: %s",
+		strings.Join(e.Modules, ", "))
+}
+
+// checkModuleCache makes sure every dependency can be added from GOMODCACHE before anything is generated.
+// Dependencies that are not pinned to a version are pinned to the newest one in the cache, since go get can't
+// resolve queries like latest without a proxy. Missing modules fail with a MissingModulesError, unless
+// SkipMissing is set, in which case they are removed from Dependencies and reported
+func (c *Configuration) checkModuleCache(ctx context.Context) error {
+	// Root may not exist yet
+	out, err := c.runIn(ctx, "", "go", "env", "GOMODCACHE")
+	if err != nil {
+		return fmt.Errorf("looking up GOMODCACHE: %w", err)
+	}
+	cache := filepath.Join(strings.TrimSpace(string(out)), "cache", "download")
+
+	var missing []string
+	var report strings.Builder
+	for _, dep := range c.selectedDependencies() {
+		if isStdlib(dep) {
+			continue
+		}
+		version, ok := cachedVersion(cache, dep, c.Dependencies[dep])
+		if !ok {
+			missing = append(missing, FormatDependency(dep, c.Dependencies[dep]))
+			continue
+		}
+		if version != c.Dependencies[dep] {
+			fmt.Fprintf(&report, "  %s pinned to %s, the newest version in the module cache\n", colorFg(dep, blueFg), version)
+			c.mu.Lock()
+			c.Dependencies[dep] = version
+			c.mu.Unlock()
+		}
+	}
+
+	if len(missing) > 0 {
+		if !c.SkipMissing {
+			return &MissingModulesError{Modules: missing}
+		}
+		for _, dep := range missing {
+			module, _ := ParseDependency(dep)
+			c.mu.Lock()
+			delete(c.Dependencies, module)
+			c.mu.Unlock()
+			fmt.Fprintf(&report, "  %s skipped, it's not in the module cache\n", colorFg(dep, redFg))
+		}
+	}
+	if report.Len() > 0 {
+		c.appendStatus(report.String() + "\n")
+	}
+	return nil
+}
+
+// cachedVersion returns the version of the module providing dep that go get would add from the cache: version
+// itself when it's a version, the newest cached release for queries like latest. dep can be a package of a module,
+// like github.com/mongodb/mongo-go-driver/mongo, as long as the cached module has it. A module nested in a cached
+// one, like go.opentelemetry.io/otel/sdk in go.opentelemetry.io/otel, is a module of its own, its files are not in
+// the zip of the other one
+func cachedVersion(cache, dep, version string) (string, bool) {
+	for module := dep; module != "." && module != ""; module = parentPath(module) {
+		v, ok := cachedModuleVersion(cache, module, version)
+		if !ok {
+			continue
+		}
+		if module == dep || hasPackage(cache, module, v, strings.TrimPrefix(dep, module+"/")) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// cachedModuleVersion is cachedVersion for the module itself
+func cachedModuleVersion(cache, module, version string) (string, bool) {
+	dir := filepath.Join(cache, filepath.FromSlash(escapeModulePath(module)), "@v")
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", false
+	}
+	if isVersion(version) {
+		_, err := os.Stat(filepath.Join(dir, version+".zip"))
+		return version, err == nil
+	}
+	// like go get, prefer releases over pre-releases
+	newest, newestRelease := "", ""
+	for _, entry := range entries {
+		v, ok := strings.CutSuffix(entry.Name(), ".zip")
+		if !ok || !isVersion(v) {
+			continue
+		}
+		if newest == "" || compareVersions(v, newest) > 0 {
+			newest = v
+		}
+		if pv, _ := parseVersion(v); pv.pre == "" && (newestRelease == "" || compareVersions(v, newestRelease) > 0) {
+			newestRelease = v
+		}
+	}
+	if newestRelease != "" {
+		return newestRelease, true
+	}
+	return newest, newest != ""
+}
+
+// hasPackage reports whether the zip of module at version in the cache has Go files in the directory pkg
+func hasPackage(cache, module, version, pkg string) bool {
+	zr, err := zip.OpenReader(filepath.Join(cache, filepath.FromSlash(escapeModulePath(module)), "@v", version+".zip"))
+	if err != nil {
+		return false
+	}
+	defer zr.Close()
+	dir := module + "@" + version + "/" + pkg
+	for _, f := range zr.File {
+		if path.Dir(f.Name) == dir && strings.HasSuffix(f.Name, ".go") {
+			return true
+		}
+	}
+	return false
+}
+
+// escapeModulePath escapes upper case letters the way the module cache does, github.com/Azure is github.com/!azure
+func escapeModulePath(module string) string {
+	var escaped strings.Builder
+	for _, r := range module {
+		if unicode.IsUpper(r) {
+			escaped.WriteRune('!')
+			r = unicode.ToLower(r)
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
+
+func isVersion(v string) bool {
+	_, ok := parseVersion(v)
+	return ok
+}
+
+type version struct {
+	nums [3]int
+	pre  string
+}
+
+func parseVersion(v string) (version, bool) {
+	v, ok := strings.CutPrefix(v, "v")
+	if !ok {
+		return version{}, false
+	}
+	v, _, _ = strings.Cut(v, "+")
+	v, pre, _ := strings.Cut(v, "-")
+	parts := strings.Split(v, ".")
+	if len(parts) != 3 {
+		return version{}, false
+	}
+	var parsed version
+	for i, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return version{}, false
+		}
+		parsed.nums[i] = n
+	}
+	parsed.pre = pre
+	return parsed, true
+}
+
+// compareVersions compares two semantic versions, releases are newer than their pre-releases
+func compareVersions(a, b string) int {
+	va, _ := parseVersion(a)
+	vb, _ := parseVersion(b)
+	for i := range va.nums {
+		if va.nums[i] != vb.nums[i] {
+			if va.nums[i] > vb.nums[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	switch {
+	case va.pre == vb.pre:
+		return 0
+	case va.pre == "":
+		return 1
+	case vb.pre == "":
+		return -1
+	}
+	return strings.Compare(va.pre, vb.pre)
+}
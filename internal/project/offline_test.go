@@ -0,0 +1,180 @@
+package project
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+// cacheRunner answers go env GOMODCACHE with its directory and records the rest of the commands
+type cacheRunner struct {
+	recordingRunner
+	modcache string
+}
+
+func (r *cacheRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	if cmd.String() == "go env GOMODCACHE" {
+		return []byte(r.modcache + "\n"), nil
+	}
+	return r.recordingRunner.Run(ctx, cmd)
+}
+
+func newModuleCache(t *testing.T, modules map[string][]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	for module, versions := range modules {
+		v := filepath.Join(dir, "cache", "download", filepath.FromSlash(escapeModulePath(module)), "@v")
+		if err := os.MkdirAll(v, 0755); err != nil {
+			t.Fatal(err)
+		}
+		for _, version := range versions {
+			if err := os.WriteFile(filepath.Join(v, version+".zip"), nil, 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	return dir
+}
+
+func offlineConfiguration(runner CommandRunner) *Configuration {
+	c := NewConfiguration("example")
+	c.FS = fsys.Mem{}
+	c.Runner = runner
+	c.Offline = true
+	c.WebLibrary = WebLibraryGin
+	c.DBLibrary = DBLibraryNone
+	c.DBProvider = DBProviderNone
+	c.Dependencies["github.com/BurntSushi/toml"] = ""
+	c.Dependencies["github.com/google/uuid"] = "v1.6.0"
+	return c
+}
+
+func TestOfflineFailsOnMissingModules(t *testing.T) {
+	runner := &cacheRunner{modcache: newModuleCache(t, map[string][]string{
+		WebLibraryGin:                {"v1.9.1", "v1.10.0"},
+		"github.com/BurntSushi/toml": {"v1.3.2"},
+	})}
+	c := offlineConfiguration(runner)
+
+	err := c.Start(context.Background())
+	var missing *MissingModulesError
+	if !errors.As(err, &missing) {
+		t.Fatalf("expected a MissingModulesError, got %v", err)
+	}
+	if len(missing.Modules) != 1 || missing.Modules[0] != "github.com/google/uuid@v1.6.0" {
+		t.Errorf("expected only uuid to be missing, got %v", missing.Modules)
+	}
+	if len(runner.cmds) != 0 {
+		t.Errorf("expected nothing to run after the check, got %v", runner.cmds)
+	}
+}
+
+func TestOfflineSkipsMissingModules(t *testing.T) {
+	runner := &cacheRunner{modcache: newModuleCache(t, map[string][]string{
+		WebLibraryGin:                {"v1.9.1", "v1.10.0", "v1.11.0-rc1"},
+		"github.com/BurntSushi/toml": {"v1.3.2"},
+	})}
+	c := offlineConfiguration(runner)
+	c.SkipMissing = true
+
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	get := "go get github.com/BurntSushi/toml@v1.3.2 " + WebLibraryGin + "@v1.10.0"
+	found := false
+	for _, cmd := range runner.cmds {
+		if strings.Join(cmd.Env, " ") != strings.Join(offlineEnv, " ") {
+			t.Errorf("expected %q to run with %v, got %v", cmd, offlineEnv, cmd.Env)
+		}
+		found = found || cmd.String() == get
+	}
+	if !found {
+		t.Errorf("expected %q, got %v", get, runner.cmds)
+	}
+	if !strings.Contains(c.GetCurrentCmd(), "github.com/google/uuid@v1.6.0") {
+		t.Errorf("expected the skipped module to be reported, got %q", c.GetCurrentCmd())
+	}
+}
+
+func TestCachedVersionNestedModule(t *testing.T) {
+	cache := filepath.Join(newModuleCache(t, map[string][]string{
+		DependencyOtel: {"v1.38.0"},
+	}), "cache", "download")
+
+	if version, ok := cachedVersion(cache, DependencyOtel, "latest"); !ok || version != "v1.38.0" {
+		t.Errorf("expected %s to be cached at v1.38.0, got %q, %v", DependencyOtel, version, ok)
+	}
+	if _, ok := cachedVersion(cache, DependencyOtelSdk, "latest"); ok {
+		t.Errorf("expected %s not to be cached along with %s", DependencyOtelSdk, DependencyOtel)
+	}
+}
+
+func TestCachedVersionPackageOfModule(t *testing.T) {
+	modcache := newModuleCache(t, nil)
+	cache := filepath.Join(modcache, "cache", "download")
+	dir := filepath.Join(cache, "github.com", "example", "lib", "@v")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Create(filepath.Join(dir, "v1.2.0.zip"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	zw := zip.NewWriter(f)
+	for _, name := range []string{"go.mod", "lib.go", "pkg/pkg.go"} {
+		if _, err := zw.Create("github.com/example/lib@v1.2.0/" + name); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if version, ok := cachedVersion(cache, "github.com/example/lib/pkg", "latest"); !ok || version != "v1.2.0" {
+		t.Errorf("expected the package to be cached at v1.2.0, got %q, %v", version, ok)
+	}
+	if _, ok := cachedVersion(cache, "github.com/example/lib/pkg", "v1.1.0"); ok {
+		t.Error("expected v1.1.0 not to be cached")
+	}
+	if _, ok := cachedVersion(cache, "github.com/example/lib/missing", "latest"); ok {
+		t.Error("expected a package the module doesn't have not to be cached")
+	}
+
+	runner := &cacheRunner{modcache: modcache}
+	c := offlineConfiguration(runner)
+	c.WebLibrary = WebLibraryNone
+	c.Dependencies = map[string]string{"github.com/example/lib/pkg": ""}
+	if err := c.Start(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := c.Dependencies["github.com/example/lib/pkg"]; got != "v1.2.0" {
+		t.Errorf("expected the package to be pinned to v1.2.0, got %q", got)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"v1.10.0", "v1.9.1", 1},
+		{"v1.9.1", "v1.9.1", 0},
+		{"v1.9.1-rc1", "v1.9.1", -1},
+		{"v2.0.0-alpha", "v2.0.0-beta", -1},
+		{"v0.0.0-20230101000000-abcdef123456", "v0.1.0", -1},
+	}
+	for _, tt := range tests {
+		if got := compareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("compareVersions(%s, %s): expected %d, got %d", tt.a, tt.b, tt.want, got)
+		}
+	}
+}
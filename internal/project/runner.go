@@ -0,0 +1,43 @@
+package project
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// Command is an external command run while generating a project
+type Command struct {
+	// Dir is the directory the command runs in, the working directory when empty
+	Dir  string   `json:"dir,omitempty"`
+	Name string   `json:"name"`
+	Args []string `json:"args,omitempty"`
+	// Env is added to the environment of the current process
+	Env []string `json:"env,omitempty"`
+}
+
+func (c Command) String() string {
+	return strings.Join(append([]string{c.Name}, c.Args...), " ")
+}
+
+// CommandRunner runs the external commands (go mod init, go get, ...) needed to generate a project
+type CommandRunner interface {
+	// Run runs cmd and returns its combined stdout and stderr
+	Run(ctx context.Context, cmd Command) ([]byte, error)
+}
+
+// OSRunner runs commands as processes of the OS
+type OSRunner struct{}
+
+func (OSRunner) Run(ctx context.Context, cmd Command) ([]byte, error) {
+	c := exec.CommandContext(ctx, cmd.Name, cmd.Args...)
+	c.Dir = cmd.Dir
+	// children of a killed command (e.g. of a hook's sh) can keep its output open, don't wait for them for long
+	c.WaitDelay = time.Second
+	if len(cmd.Env) > 0 {
+		c.Env = append(os.Environ(), cmd.Env...)
+	}
+	return c.CombinedOutput()
+}
@@ -4,42 +4,51 @@ import (
 	"embed"
 )
 
-//go:embed "embedded/db_common"
+//go:embed "embedded/db_common" "embedded/mock_helpers.tmpl"
 var common embed.FS
 
-//go:embed "embedded/gin" "embedded/Dockerfile"
+//go:embed "embedded/gin" "embedded/health" "embedded/Dockerfile" "embedded/build_flags.tmpl" "embedded/handler_tests.tmpl"
 var gin embed.FS
 
-//go:embed "embedded/gin/pkg/httphelpers" "embedded/gin/pkg/taskutils" "embedded/Dockerfile"
+//go:embed "embedded/gin/pkg/httphelpers" "embedded/gin/pkg/taskutils" "embedded/Dockerfile" "embedded/build_flags.tmpl"
 var ginLean embed.FS
 
-//go:embed "embedded/fiber" "embedded/Dockerfile"
+//go:embed "embedded/fiber" "embedded/health" "embedded/Dockerfile" "embedded/build_flags.tmpl" "embedded/handler_tests.tmpl"
 var fiber embed.FS
 
-//go:embed "embedded/fiber/pkg/httphelpers" "embedded/fiber/pkg/taskutils" "embedded/Dockerfile"
+//go:embed "embedded/fiber/pkg/httphelpers" "embedded/fiber/pkg/taskutils" "embedded/Dockerfile" "embedded/build_flags.tmpl"
 var fiberLean embed.FS
 
-//go:embed "embedded/http" "embedded/httpcommon" "embedded/Dockerfile"
+//go:embed "embedded/http" "embedded/httpcommon" "embedded/health" "embedded/health_http" "embedded/Dockerfile" "embedded/build_flags.tmpl" "embedded/handler_tests.tmpl"
 var http embed.FS
 
-//go:embed "embedded/httpcommon" "embedded/Dockerfile"
+//go:embed "embedded/httpcommon" "embedded/Dockerfile" "embedded/build_flags.tmpl"
 var httpLean embed.FS
 
-//go:embed "embedded/gorillamux" "embedded/httpcommon" "embedded/Dockerfile"
+//go:embed "embedded/gorillamux" "embedded/httpcommon" "embedded/health" "embedded/health_http" "embedded/Dockerfile" "embedded/build_flags.tmpl" "embedded/handler_tests.tmpl"
 var gorillamux embed.FS
 
-//go:embed "embedded/httpcommon" "embedded/Dockerfile"
+//go:embed "embedded/httpcommon" "embedded/Dockerfile" "embedded/build_flags.tmpl"
 var gorillamuxLean embed.FS
 
-//go:embed "embedded/sql"
+//go:embed "embedded/sql" "embedded/repo_tests.tmpl"
 var sql embed.FS
 
-//go:embed "embedded/sqlx"
+//go:embed "embedded/sqlx" "embedded/repo_tests.tmpl"
 var sqlx embed.FS
 
-//go:embed "embedded/gorm"
+//go:embed "embedded/gorm" "embedded/repo_tests.tmpl"
 var gorm embed.FS
 
+//go:embed "embedded/sql/cmd/init_example_db"
+var sqlLean embed.FS
+
+//go:embed "embedded/sqlx/cmd/init_example_db"
+var sqlxLean embed.FS
+
+//go:embed "embedded/gorm/cmd/init_example_db"
+var gormLean embed.FS
+
 //go:embed "embedded/mysql.tmpl"
 var mysql embed.FS
 
@@ -52,5 +61,87 @@ var gormMysql embed.FS
 //go:embed "embedded/gorm_postgresql.tmpl"
 var gormPostgresql embed.FS
 
-//go:embed "embedded/db_common/cmd/init_example_db" "embedded/db_common/internal"
+//go:embed "embedded/db_common/cmd/init_example_db" "embedded/db_common/internal" "embedded/mock_helpers.tmpl"
 var commonLean embed.FS
+
+//go:embed "embedded/mocks_fakes"
+var mocksFakes embed.FS
+
+//go:embed "embedded/mocks_fakes/internal/helloworld/mocks/repository.go.tmpl"
+var mocksFakesLean embed.FS
+
+//go:embed "embedded/mocks_gomock"
+var mocksGomock embed.FS
+
+//go:embed "embedded/mocks_gomock/internal/helloworld/mocks/repository.go.tmpl"
+var mocksGomockLean embed.FS
+
+// the files are hidden, so they are only embedded with all:
+//
+//go:embed all:embedded/git
+var git embed.FS
+
+//go:embed all:embedded/lint
+var lint embed.FS
+
+//go:embed "embedded/kustomize"
+var kustomize embed.FS
+
+//go:embed "embedded/kustomize_secret"
+var kustomizeSecret embed.FS
+
+// _helpers.tpl is only embedded with all:
+//
+//go:embed all:embedded/helm
+var helm embed.FS
+
+//go:embed all:embedded/github
+var github embed.FS
+
+//go:embed all:embedded/gitlab
+var gitlab embed.FS
+
+//go:embed "embedded/make" "embedded/build_flags.tmpl"
+var makefile embed.FS
+
+//go:embed "embedded/taskfile" "embedded/build_flags.tmpl"
+var taskfile embed.FS
+
+//go:embed "embedded/compose"
+var compose embed.FS
+
+//go:embed "embedded/logging"
+var logging embed.FS
+
+//go:embed "embedded/logging_slog"
+var loggingSlog embed.FS
+
+//go:embed "embedded/logging_zap"
+var loggingZap embed.FS
+
+//go:embed "embedded/logging_zerolog"
+var loggingZerolog embed.FS
+
+//go:embed "embedded/logging_gin"
+var loggingGin embed.FS
+
+//go:embed "embedded/logging_fiber"
+var loggingFiber embed.FS
+
+//go:embed "embedded/logging_http"
+var loggingHttp embed.FS
+
+//go:embed "embedded/telemetry"
+var telemetry embed.FS
+
+//go:embed "embedded/auth"
+var auth embed.FS
+
+//go:embed "embedded/auth_gin"
+var authGin embed.FS
+
+//go:embed "embedded/auth_fiber"
+var authFiber embed.FS
+
+//go:embed "embedded/auth_http"
+var authHttp embed.FS
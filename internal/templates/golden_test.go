@@ -0,0 +1,177 @@
+package templates
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+var update = flag.Bool("update", false, "regenerate the golden files in "+goldenDir)
+
+const goldenDir = "testdata/golden"
+
+// TestGolden renders every combination in memory and compares it byte for byte with testdata/golden/<combination>,
+// run `go test ./internal/templates -run TestGolden -update` to accept the new output
+func TestGolden(t *testing.T) {
+	names := map[string]struct{}{}
+	for _, c := range combinations() {
+		c := c
+		names[c.name] = struct{}{}
+		t.Run(c.name, func(t *testing.T) {
+			got := fsys.Mem{}
+			if err := buildTemplate(got, c.proj, selectEmbs(c.proj)...); err != nil {
+				t.Fatalf("%s: rendering templates failed: %v", c.name, err)
+			}
+			// the generated projects go through `go fmt`, so the golden files hold what users actually get
+			if err := formatGo(got); err != nil {
+				t.Fatalf("%s: %v", c.name, err)
+			}
+
+			dir := filepath.Join(goldenDir, c.name)
+			if *update {
+				writeGolden(t, dir, got)
+				return
+			}
+
+			want := readGolden(t, dir)
+			for _, name := range sortedKeys(got, want) {
+				gotContent, inGot := got[name]
+				wantContent, inWant := want[name]
+				switch {
+				case !inWant:
+					t.Errorf("%s: unexpected file %s, run with -update if it's expected", c.name, name)
+				case !inGot:
+					t.Errorf("%s: missing file %s", c.name, name)
+				case !bytes.Equal(gotContent, wantContent):
+					t.Errorf("%s: %s differs from the golden file, %s", c.name, name, firstDiff(gotContent, wantContent))
+				}
+			}
+		})
+	}
+
+	if *update {
+		removeStaleGolden(t, names)
+	}
+}
+
+// formatGo formats every Go file in files the way gofmt does
+func formatGo(files fsys.Mem) error {
+	for name, content := range files {
+		if !strings.HasSuffix(name, ".go") {
+			continue
+		}
+		formatted, err := format.Source(content)
+		if err != nil {
+			return fmt.Errorf("formatting %s failed: %w", name, err)
+		}
+		files[name] = formatted
+	}
+	return nil
+}
+
+// removeStaleGolden removes the golden directories of combinations that no longer exist
+func removeStaleGolden(t *testing.T, names map[string]struct{}) {
+	t.Helper()
+
+	entries, err := os.ReadDir(goldenDir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if _, ok := names[entry.Name()]; ok {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(goldenDir, entry.Name())); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func writeGolden(t *testing.T, dir string, files fsys.Mem) {
+	t.Helper()
+
+	if err := os.RemoveAll(dir); err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		dest := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(dest, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func readGolden(t *testing.T, dir string) fsys.Mem {
+	t.Helper()
+
+	files := fsys.Mem{}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		// projects without files have no golden directory
+		return files
+	}
+	err := filepath.WalkDir(dir, func(pathStr string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		content, err := os.ReadFile(pathStr)
+		if err != nil {
+			return err
+		}
+		name, err := filepath.Rel(dir, pathStr)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(name)] = content
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("reading golden files failed: %v", err)
+	}
+	return files
+}
+
+func sortedKeys(mems ...fsys.Mem) []string {
+	seen := map[string]struct{}{}
+	var keys []string
+	for _, mem := range mems {
+		for key := range mem {
+			if _, ok := seen[key]; !ok {
+				seen[key] = struct{}{}
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// firstDiff describes the first line where got and want differ
+func firstDiff(got, want []byte) string {
+	gotLines := strings.Split(string(got), "\n")
+	wantLines := strings.Split(string(want), "\n")
+	for i := 0; i < len(gotLines) || i < len(wantLines); i++ {
+		var g, w string
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if g != w {
+			return fmt.Sprintf("line %d:\n\tgot:  %q\n\twant: %q", i+1, g, w)
+		}
+	}
+	return "contents differ"
+}
@@ -1,184 +1,385 @@
-package templates
-
-import (
-	"embed"
-	"io/fs"
-	"os"
-	"path"
-	"strings"
-	"text/template"
-
-	"github.com/fedevilensky/go-scaffold/internal/project"
-)
-
-type templateFunc struct {
-	build func(proj *project.Configuration) error
-}
-
-func (t *templateFunc) Build(proj *project.Configuration) error {
-	return t.build(proj)
-}
-
-func LoadFullTemplates() *templateFunc {
-	return &templateFunc{
-		build: func(proj *project.Configuration) error {
-			return buildTemplate(proj, selectEmbs(proj)...)
-		},
-	}
-}
-
-func selectEmbs(proj *project.Configuration) []embed.FS {
-	var embs []embed.FS
-	if isFullProject(proj) {
-		embs = []embed.FS{common}
-		switch proj.WebLibrary {
-		case project.WebLibraryFiber:
-			embs = append(embs, fiber)
-		case project.WebLibraryGin:
-			embs = append(embs, gin)
-		case project.WebLibraryGorillamux:
-			embs = append(embs, gorillamux)
-		case project.WebLibraryHttp:
-			embs = append(embs, http)
-		}
-
-		switch proj.DBLibrary {
-		case project.DBLibraryGorm:
-			embs = append(embs, gorm)
-		case project.DBLibrarySql:
-			embs = append(embs, sql)
-		case project.DBLibrarySqlx:
-			embs = append(embs, sqlx)
-		}
-
-		switch proj.DBProvider {
-		case project.DBProviderPostgres:
-			embs = append(embs, postgresql)
-		case project.DBProviderMysql:
-			embs = append(embs, mysql)
-		case project.DBProviderGormMysql:
-			embs = append(embs, gormMysql)
-		case project.DBProviderGormPostgres:
-			embs = append(embs, gormPostgresql)
-		default:
-			embs = append(embs, mysql)
-		}
-
-	} else if proj.WebLibrary != project.WebLibraryNone {
-		embs = []embed.FS{}
-		switch proj.WebLibrary {
-		case project.WebLibraryFiber:
-			embs = append(embs, fiberLean)
-		case project.WebLibraryGin:
-			embs = append(embs, ginLean)
-		case project.WebLibraryGorillamux:
-			embs = append(embs, gorillamuxLean)
-		case project.WebLibraryHttp:
-			embs = append(embs, httpLean)
-		}
-	} else if proj.DBLibrary != project.DBLibraryNone {
-		embs = []embed.FS{commonLean}
-		switch proj.DBProvider {
-		case project.DBProviderPostgres:
-			embs = append(embs, postgresql)
-		case project.DBProviderMysql:
-			embs = append(embs, mysql)
-		case project.DBProviderGormMysql:
-			embs = append(embs, gormMysql)
-		case project.DBProviderGormPostgres:
-			embs = append(embs, gormPostgresql)
-		default:
-			embs = append(embs, mysql)
-		}
-	}
-
-	return embs
-}
-
-func isFullProject(proj *project.Configuration) bool {
-	return proj.WebLibrary != project.WebLibraryNone && proj.DBLibrary != project.DBLibraryNone
-}
-
-func buildTemplate(proj *project.Configuration, embs ...embed.FS) error {
-	tmpl, err := createTemplate(embs...)
-
-	if err != nil {
-		return err
-	}
-
-	err = createFiles(proj, tmpl, embs...)
-	return err
-}
-
-func createTemplate(embs ...embed.FS) (tmpl *template.Template, err error) {
-	tmpl = template.New("")
-	for _, emb := range embs {
-		patterns := []string{}
-		err := fs.WalkDir(emb, ".", func(pathStr string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if !strings.HasSuffix(pathStr, ".tmpl") {
-				return nil
-			}
-
-			patterns = append(patterns, pathStr)
-			return nil
-		})
-		if err != nil {
-			return nil, err
-		}
-		tmpl, err = tmpl.ParseFS(emb, patterns...)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	return tmpl, nil
-}
-
-func createFiles(proj *project.Configuration, tmpl *template.Template, embs ...embed.FS) error {
-	for _, emb := range embs {
-		err := fs.WalkDir(emb, ".", func(pathStr string, d fs.DirEntry, err error) error {
-			if err != nil {
-				return err
-			}
-			if !isOneOf(pathStr, ".go.tmpl", "Dockerfile.tmpl") {
-				return nil
-			}
-			pathParts := strings.Split(pathStr, "/")
-			destPath := strings.Join(pathParts[2:], "/")
-			destPath = strings.TrimRight(destPath, ".tmpl")
-			templateName := path.Base(pathStr)
-			err = os.MkdirAll(path.Dir(destPath), 0755)
-			if err != nil {
-				panic(err)
-			}
-			f, err := os.Create(destPath)
-			if err != nil {
-				panic(err)
-			}
-			defer f.Close()
-
-			err = tmpl.Lookup(templateName).Execute(f, proj)
-			if err != nil {
-				panic(err)
-			}
-			return nil
-		})
-		if err != nil {
-			return err
-		}
-	}
-
-	return nil
-}
-
-func isOneOf(pathStr string, strs ...string) bool {
-	for _, str := range strs {
-		if strings.HasSuffix(pathStr, str) {
-			return true
-		}
-	}
-	return false
-}
+package templates
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+	"github.com/fedevilensky/go-scaffold/internal/project"
+)
+
+type templateFunc struct {
+	build func(proj *project.Configuration) error
+}
+
+func (t *templateFunc) Build(proj *project.Configuration) error {
+	return t.build(proj)
+}
+
+// LoadFullTemplates returns the built-in templates, followed by the template packs.
+// Packs are rendered after the built-in templates, so they can replace generated files,
+// and can use every template the built-in ones define
+func LoadFullTemplates(packs ...fs.FS) *templateFunc {
+	return &templateFunc{
+		build: func(proj *project.Configuration) error {
+			w := proj.FileWriter()
+			embs := selectEmbs(proj)
+			tmpl, err := createTemplate(embs...)
+			if err != nil {
+				return err
+			}
+			err = createFiles(w, proj, tmpl, embs...)
+			if err != nil {
+				return err
+			}
+			return createPackFiles(w, proj, tmpl, packs...)
+		},
+	}
+}
+
+func selectEmbs(proj *project.Configuration) []embed.FS {
+	var embs []embed.FS
+	if isFullProject(proj) {
+		embs = []embed.FS{common}
+		switch proj.WebLibrary {
+		case project.WebLibraryFiber:
+			embs = append(embs, fiber)
+		case project.WebLibraryGin:
+			embs = append(embs, gin)
+		case project.WebLibraryGorillamux:
+			embs = append(embs, gorillamux)
+		case project.WebLibraryHttp:
+			embs = append(embs, http)
+		}
+
+		switch proj.DBLibrary {
+		case project.DBLibraryGorm:
+			embs = append(embs, gorm)
+		case project.DBLibrarySql:
+			embs = append(embs, sql)
+		case project.DBLibrarySqlx:
+			embs = append(embs, sqlx)
+		}
+
+		switch proj.DBProvider {
+		case project.DBProviderPostgres:
+			embs = append(embs, postgresql)
+		case project.DBProviderMysql:
+			embs = append(embs, mysql)
+		case project.DBProviderGormMysql:
+			embs = append(embs, gormMysql)
+		case project.DBProviderGormPostgres:
+			embs = append(embs, gormPostgresql)
+		default:
+			embs = append(embs, mysql)
+		}
+
+		switch proj.Mocks {
+		case project.MocksFakes:
+			embs = append(embs, mocksFakes)
+		case project.MocksGomock:
+			embs = append(embs, mocksGomock)
+		}
+
+	} else if proj.WebLibrary != project.WebLibraryNone {
+		embs = []embed.FS{}
+		switch proj.WebLibrary {
+		case project.WebLibraryFiber:
+			embs = append(embs, fiberLean)
+		case project.WebLibraryGin:
+			embs = append(embs, ginLean)
+		case project.WebLibraryGorillamux:
+			embs = append(embs, gorillamuxLean)
+		case project.WebLibraryHttp:
+			embs = append(embs, httpLean)
+		}
+	} else if proj.DBLibrary != project.DBLibraryNone {
+		embs = []embed.FS{commonLean}
+		switch proj.DBLibrary {
+		case project.DBLibraryGorm:
+			embs = append(embs, gormLean)
+		case project.DBLibrarySql:
+			embs = append(embs, sqlLean)
+		case project.DBLibrarySqlx:
+			embs = append(embs, sqlxLean)
+		}
+
+		switch proj.DBProvider {
+		case project.DBProviderPostgres:
+			embs = append(embs, postgresql)
+		case project.DBProviderMysql:
+			embs = append(embs, mysql)
+		case project.DBProviderGormMysql:
+			embs = append(embs, gormMysql)
+		case project.DBProviderGormPostgres:
+			embs = append(embs, gormPostgresql)
+		default:
+			embs = append(embs, mysql)
+		}
+
+		// there are no handlers in lean projects, so only the repository needs a mock
+		switch proj.Mocks {
+		case project.MocksFakes:
+			embs = append(embs, mocksFakesLean)
+		case project.MocksGomock:
+			embs = append(embs, mocksGomockLean)
+		}
+	}
+
+	if proj.StructuredLogging() {
+		embs = append(embs, logging)
+		switch proj.Logging {
+		case project.LoggingSlog:
+			embs = append(embs, loggingSlog)
+		case project.LoggingZap:
+			embs = append(embs, loggingZap)
+		case project.LoggingZerolog:
+			embs = append(embs, loggingZerolog)
+		}
+		switch proj.WebLibrary {
+		case project.WebLibraryGin:
+			embs = append(embs, loggingGin)
+		case project.WebLibraryFiber:
+			embs = append(embs, loggingFiber)
+		case project.WebLibraryGorillamux, project.WebLibraryHttp:
+			embs = append(embs, loggingHttp)
+		}
+	}
+
+	if proj.InstrumentsTelemetry() {
+		embs = append(embs, telemetry)
+	}
+
+	if proj.GeneratesAuth() {
+		embs = append(embs, auth)
+		switch proj.WebLibrary {
+		case project.WebLibraryGin:
+			embs = append(embs, authGin)
+		case project.WebLibraryFiber:
+			embs = append(embs, authFiber)
+		case project.WebLibraryGorillamux, project.WebLibraryHttp:
+			embs = append(embs, authHttp)
+		}
+	}
+
+	if proj.InitsGit() {
+		embs = append(embs, git)
+	}
+
+	if proj.Lint {
+		embs = append(embs, lint)
+	}
+
+	switch proj.Deploy {
+	case project.DeployKustomize:
+		embs = append(embs, kustomize)
+		if proj.DBLibrary != project.DBLibraryNone {
+			embs = append(embs, kustomizeSecret)
+		}
+	case project.DeployHelm:
+		embs = append(embs, helm)
+	}
+
+	switch proj.CI {
+	case project.CIGitHub:
+		embs = append(embs, github)
+	case project.CIGitLab:
+		embs = append(embs, gitlab)
+	}
+
+	switch proj.Tasks {
+	case project.TasksMake:
+		embs = append(embs, makefile)
+	case project.TasksTaskfile:
+		embs = append(embs, taskfile)
+	}
+	// the database the compose-up task starts, its credentials are defined next to the DSN
+	if proj.Tasks != project.TasksNone && proj.DBLibrary != project.DBLibraryNone {
+		embs = append(embs, compose)
+	}
+
+	return embs
+}
+
+func isFullProject(proj *project.Configuration) bool {
+	return proj.WebLibrary != project.WebLibraryNone && proj.DBLibrary != project.DBLibraryNone
+}
+
+func buildTemplate(w fsys.Writer, proj *project.Configuration, embs ...embed.FS) error {
+	tmpl, err := createTemplate(embs...)
+
+	if err != nil {
+		return err
+	}
+
+	err = createFiles(w, proj, tmpl, embs...)
+	return err
+}
+
+func createTemplate(embs ...embed.FS) (tmpl *template.Template, err error) {
+	tmpl = template.New("")
+	for _, emb := range embs {
+		patterns := []string{}
+		err := fs.WalkDir(emb, ".", func(pathStr string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !strings.HasSuffix(pathStr, ".tmpl") {
+				return nil
+			}
+
+			patterns = append(patterns, pathStr)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		tmpl, err = tmpl.ParseFS(emb, patterns...)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return tmpl, nil
+}
+
+func createFiles(w fsys.Writer, proj *project.Configuration, tmpl *template.Template, embs ...embed.FS) error {
+	for _, emb := range embs {
+		err := fs.WalkDir(emb, ".", func(pathStr string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() {
+				return err
+			}
+			pathParts := strings.Split(pathStr, "/")
+			destPath := strings.Join(pathParts[2:], "/")
+			// Helm templates use the same delimiters as text/template, they are copied as they are
+			if strings.Contains(pathStr, "/helm/templates/") {
+				return copyFile(w, emb, pathStr, destPath)
+			}
+			if !isOneOf(pathStr, ".go.tmpl", "Dockerfile.tmpl", ".gitignore.tmpl", ".gitattributes.tmpl",
+				"Makefile.tmpl", "Taskfile.yml.tmpl", "compose.yaml.tmpl",
+				".golangci.yml.tmpl", ".editorconfig.tmpl", ".pre-commit-config.yaml.tmpl",
+				"workflows/ci.yml.tmpl", ".gitlab-ci.yml.tmpl",
+				"kustomization.yaml.tmpl", "deployment.yaml.tmpl", "service.yaml.tmpl", "secret.yaml.tmpl",
+				"Chart.yaml.tmpl", "values.yaml.tmpl") {
+				return nil
+			}
+			destPath = strings.TrimSuffix(destPath, ".tmpl")
+			return createFile(w, proj, tmpl, path.Base(pathStr), destPath)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func createFile(w fsys.Writer, proj *project.Configuration, tmpl *template.Template, templateName, destPath string) (err error) {
+	t := tmpl.Lookup(templateName)
+	if t == nil {
+		return fmt.Errorf("template %s is not defined", templateName)
+	}
+	err = w.MkdirAll(path.Dir(destPath), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := w.Create(destPath)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := f.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return t.Execute(f, proj)
+}
+
+// PackManifest is the file at the root of a template pack with its settings, it's not written to the project
+const PackManifest = "pack.json"
+
+// createPackFiles renders every file of the packs to the same path, without the .tmpl suffix.
+// Files without the suffix are copied as they are, and templates whose name starts with "_" are only
+// parsed, so other files of the pack can use what they define
+func createPackFiles(w fsys.Writer, proj *project.Configuration, base *template.Template, packs ...fs.FS) error {
+	for _, pack := range packs {
+		tmpl, err := base.Clone()
+		if err != nil {
+			return err
+		}
+		var files []string
+		err = fs.WalkDir(pack, ".", func(pathStr string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || pathStr == PackManifest {
+				return err
+			}
+			files = append(files, pathStr)
+			if !strings.HasSuffix(pathStr, ".tmpl") {
+				return nil
+			}
+			content, err := fs.ReadFile(pack, pathStr)
+			if err != nil {
+				return err
+			}
+			_, err = tmpl.New(pathStr).Parse(string(content))
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		for _, pathStr := range files {
+			if strings.HasPrefix(path.Base(pathStr), "_") {
+				continue
+			}
+			err = createPackFile(w, proj, tmpl, pack, pathStr)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func createPackFile(w fsys.Writer, proj *project.Configuration, tmpl *template.Template, pack fs.FS, pathStr string) (err error) {
+	destPath := strings.TrimSuffix(pathStr, ".tmpl")
+	if destPath != pathStr {
+		return createFile(w, proj, tmpl, pathStr, destPath)
+	}
+	return copyFile(w, pack, pathStr, destPath)
+}
+
+// copyFile writes the file at pathStr to destPath as it is, without rendering it
+func copyFile(w fsys.Writer, files fs.FS, pathStr, destPath string) (err error) {
+	content, err := fs.ReadFile(files, pathStr)
+	if err != nil {
+		return err
+	}
+	err = w.MkdirAll(path.Dir(destPath), 0755)
+	if err != nil {
+		return err
+	}
+	f, err := w.Create(destPath)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(content)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	return err
+}
+
+func isOneOf(pathStr string, strs ...string) bool {
+	for _, str := range strs {
+		if strings.HasSuffix(pathStr, str) {
+			return true
+		}
+	}
+	return false
+}
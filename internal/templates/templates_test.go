@@ -0,0 +1,270 @@
+package templates
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+	"github.com/fedevilensky/go-scaffold/internal/project"
+)
+
+// fixtureDir holds the go.mod and go.sum every rendered project is compiled with,
+// its module name is the project name used by the combinations
+const fixtureDir = "testdata/fixture"
+
+type combination struct {
+	name string
+	proj *project.Configuration
+}
+
+func newCombination(name, webLibrary, dbLibrary, dbProvider string, opts ...func(*project.Configuration)) combination {
+	proj := project.NewConfiguration("example")
+	proj.WebLibrary = webLibrary
+	proj.DBLibrary = dbLibrary
+	proj.DBProvider = dbProvider
+	for _, opt := range opts {
+		opt(proj)
+	}
+	return combination{name: name, proj: proj}
+}
+
+func withTestify(proj *project.Configuration) {
+	proj.Dependencies[project.DependencyTestify] = ""
+}
+
+func withGit(proj *project.Configuration) {
+	proj.Git = project.GitCommit
+}
+
+func withTasks(tasks string) func(*project.Configuration) {
+	return func(proj *project.Configuration) {
+		proj.Tasks = tasks
+	}
+}
+
+func withCI(ci string) func(*project.Configuration) {
+	return func(proj *project.Configuration) {
+		proj.CI = ci
+		proj.GoVersion = "1.26.0"
+	}
+}
+
+func withDeploy(deploy string) func(*project.Configuration) {
+	return func(proj *project.Configuration) {
+		proj.Deploy = deploy
+	}
+}
+
+func withLogging(logging string) func(*project.Configuration) {
+	return func(proj *project.Configuration) {
+		proj.Logging = logging
+	}
+}
+
+func withTelemetry(proj *project.Configuration) {
+	proj.Telemetry = true
+}
+
+func withAuth(proj *project.Configuration) {
+	proj.Auth = true
+}
+
+func withLint(proj *project.Configuration) {
+	proj.Lint = true
+}
+
+func withVendor(proj *project.Configuration) {
+	proj.DoVendor = true
+}
+
+func withMocks(mocks string) func(*project.Configuration) {
+	return func(proj *project.Configuration) {
+		proj.Mocks = mocks
+	}
+}
+
+// combinations returns every WebLibrary × DBLibrary × DBProvider combination, lean variants included,
+// plus a few variants for options that change the generated code
+func combinations() []combination {
+	webLibraries := map[string]string{
+		"gin":        project.WebLibraryGin,
+		"fiber":      project.WebLibraryFiber,
+		"gorillamux": project.WebLibraryGorillamux,
+		"http":       project.WebLibraryHttp,
+		"noweb":      project.WebLibraryNone,
+	}
+	dbs := []struct {
+		name, library, provider string
+	}{
+		{"sql-postgres", project.DBLibrarySql, project.DBProviderPostgres},
+		{"sql-mysql", project.DBLibrarySql, project.DBProviderMysql},
+		{"sqlx-postgres", project.DBLibrarySqlx, project.DBProviderPostgres},
+		{"sqlx-mysql", project.DBLibrarySqlx, project.DBProviderMysql},
+		{"gorm-postgres", project.DBLibraryGorm, project.DBProviderGormPostgres},
+		{"gorm-mysql", project.DBLibraryGorm, project.DBProviderGormMysql},
+		{"nodb", project.DBLibraryNone, project.DBProviderNone},
+	}
+
+	var combs []combination
+	for webName, web := range webLibraries {
+		for _, db := range dbs {
+			combs = append(combs, newCombination(webName+"-"+db.name, web, db.library, db.provider))
+		}
+		if web == project.WebLibraryNone {
+			continue
+		}
+		combs = append(combs,
+			newCombination(webName+"-sqlx-postgres-testify-fakes", web, project.DBLibrarySqlx, project.DBProviderPostgres,
+				withTestify, withMocks(project.MocksFakes)),
+			newCombination(webName+"-gorm-mysql-gomock", web, project.DBLibraryGorm, project.DBProviderGormMysql,
+				withMocks(project.MocksGomock)),
+		)
+	}
+	combs = append(combs,
+		newCombination("noweb-sql-postgres-testify-gomock", project.WebLibraryNone, project.DBLibrarySql, project.DBProviderPostgres,
+			withTestify, withMocks(project.MocksGomock)),
+		newCombination("noweb-gorm-mysql-fakes", project.WebLibraryNone, project.DBLibraryGorm, project.DBProviderGormMysql,
+			withMocks(project.MocksFakes)),
+		newCombination("noweb-nodb-git", project.WebLibraryNone, project.DBLibraryNone, project.DBProviderNone,
+			withGit),
+		newCombination("noweb-nodb-git-vendor", project.WebLibraryNone, project.DBLibraryNone, project.DBProviderNone,
+			withGit, withVendor),
+		newCombination("gin-sql-postgres-gomock-git", project.WebLibraryGin, project.DBLibrarySql, project.DBProviderPostgres,
+			withMocks(project.MocksGomock), withGit),
+		newCombination("fiber-sqlx-postgres-gomock-make-vendor", project.WebLibraryFiber, project.DBLibrarySqlx, project.DBProviderPostgres,
+			withMocks(project.MocksGomock), withTasks(project.TasksMake), withVendor),
+		newCombination("noweb-gorm-mysql-task", project.WebLibraryNone, project.DBLibraryGorm, project.DBProviderGormMysql,
+			withTasks(project.TasksTaskfile)),
+		newCombination("http-nodb-make", project.WebLibraryHttp, project.DBLibraryNone, project.DBProviderNone,
+			withTasks(project.TasksMake)),
+		newCombination("gin-gorm-postgres-lint", project.WebLibraryGin, project.DBLibraryGorm, project.DBProviderGormPostgres,
+			withLint),
+		newCombination("noweb-sqlx-mysql-lint-vendor", project.WebLibraryNone, project.DBLibrarySqlx, project.DBProviderMysql,
+			withLint, withVendor),
+		newCombination("gin-sqlx-postgres-lint-github", project.WebLibraryGin, project.DBLibrarySqlx, project.DBProviderPostgres,
+			withLint, withCI(project.CIGitHub)),
+		newCombination("fiber-gorm-mysql-gitlab", project.WebLibraryFiber, project.DBLibraryGorm, project.DBProviderGormMysql,
+			withCI(project.CIGitLab)),
+		newCombination("http-nodb-github-vendor", project.WebLibraryHttp, project.DBLibraryNone, project.DBProviderNone,
+			withCI(project.CIGitHub), withVendor),
+		newCombination("noweb-sql-mysql-lint-gitlab", project.WebLibraryNone, project.DBLibrarySql, project.DBProviderMysql,
+			withLint, withCI(project.CIGitLab)),
+		newCombination("gin-sql-postgres-kustomize", project.WebLibraryGin, project.DBLibrarySql, project.DBProviderPostgres,
+			withDeploy(project.DeployKustomize)),
+		newCombination("gorillamux-gorm-mysql-helm", project.WebLibraryGorillamux, project.DBLibraryGorm, project.DBProviderGormMysql,
+			withDeploy(project.DeployHelm)),
+		newCombination("fiber-nodb-kustomize", project.WebLibraryFiber, project.DBLibraryNone, project.DBProviderNone,
+			withDeploy(project.DeployKustomize)),
+		newCombination("http-nodb-helm", project.WebLibraryHttp, project.DBLibraryNone, project.DBProviderNone,
+			withDeploy(project.DeployHelm)),
+		newCombination("gin-sqlx-postgres-slog", project.WebLibraryGin, project.DBLibrarySqlx, project.DBProviderPostgres,
+			withLogging(project.LoggingSlog)),
+		newCombination("fiber-gorm-postgres-zap", project.WebLibraryFiber, project.DBLibraryGorm, project.DBProviderGormPostgres,
+			withLogging(project.LoggingZap)),
+		newCombination("gorillamux-sql-mysql-zerolog-lint", project.WebLibraryGorillamux, project.DBLibrarySql, project.DBProviderMysql,
+			withLogging(project.LoggingZerolog), withLint),
+		newCombination("http-gorm-mysql-slog", project.WebLibraryHttp, project.DBLibraryGorm, project.DBProviderGormMysql,
+			withLogging(project.LoggingSlog)),
+		newCombination("http-nodb-zap", project.WebLibraryHttp, project.DBLibraryNone, project.DBProviderNone,
+			withLogging(project.LoggingZap)),
+		newCombination("fiber-nodb-zerolog", project.WebLibraryFiber, project.DBLibraryNone, project.DBProviderNone,
+			withLogging(project.LoggingZerolog)),
+		newCombination("gin-sql-postgres-telemetry", project.WebLibraryGin, project.DBLibrarySql, project.DBProviderPostgres,
+			withTelemetry),
+		newCombination("fiber-sqlx-mysql-telemetry", project.WebLibraryFiber, project.DBLibrarySqlx, project.DBProviderMysql,
+			withTelemetry),
+		newCombination("gorillamux-gorm-postgres-telemetry-slog-lint", project.WebLibraryGorillamux, project.DBLibraryGorm, project.DBProviderGormPostgres,
+			withTelemetry, withLogging(project.LoggingSlog), withLint),
+		newCombination("http-sqlx-postgres-telemetry-zap", project.WebLibraryHttp, project.DBLibrarySqlx, project.DBProviderPostgres,
+			withTelemetry, withLogging(project.LoggingZap)),
+		newCombination("http-nodb-telemetry", project.WebLibraryHttp, project.DBLibraryNone, project.DBProviderNone,
+			withTelemetry),
+		newCombination("gin-nodb-auth", project.WebLibraryGin, project.DBLibraryNone, project.DBProviderNone,
+			withAuth),
+		newCombination("fiber-gorm-mysql-auth", project.WebLibraryFiber, project.DBLibraryGorm, project.DBProviderGormMysql,
+			withAuth),
+		newCombination("gorillamux-nodb-auth-lint", project.WebLibraryGorillamux, project.DBLibraryNone, project.DBProviderNone,
+			withAuth, withLint),
+		newCombination("http-sql-postgres-auth-zap", project.WebLibraryHttp, project.DBLibrarySql, project.DBProviderPostgres,
+			withAuth, withLogging(project.LoggingZap)),
+	)
+
+	return combs
+}
+
+// render writes the project for c into dir, along with the fixture go.mod and go.sum
+func render(t *testing.T, c combination, dir string) {
+	t.Helper()
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"go.mod", "go.sum"} {
+		b, err := os.ReadFile(filepath.Join(fixtureDir, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), b, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := buildTemplate(fsys.OS(dir), c.proj, selectEmbs(c.proj)...); err != nil {
+		t.Fatalf("%s: rendering templates failed: %v", c.name, err)
+	}
+}
+
+// goOffline runs the go command in dir without network access, only the local module cache is used
+func goOffline(dir string, args ...string) (string, error) {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off", "GOWORK=off", "GOTOOLCHAIN=local")
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+	err := cmd.Run()
+	return out.String(), err
+}
+
+func TestTemplatesCompile(t *testing.T) {
+	if testing.Short() {
+		t.Skip("compiling every template combination is slow, skipping in short mode")
+	}
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go toolchain not found in PATH")
+	}
+
+	root := t.TempDir()
+	combs := combinations()
+	for _, c := range combs {
+		render(t, c, filepath.Join(root, c.name))
+	}
+
+	for _, c := range combs {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			t.Parallel()
+
+			dir := filepath.Join(root, c.name)
+			for _, args := range [][]string{{"build", "./..."}, {"vet", "./..."}, {"test", "./..."}} {
+				out, err := goOffline(dir, args...)
+				if err == nil {
+					continue
+				}
+				if strings.Contains(out, "matched no packages") {
+					// nothing to compile, there's neither a web library nor a DB library
+					return
+				}
+				if strings.Contains(out, "GOPROXY=off") {
+					t.Skipf("%s: the module cache lacks fixture dependencies, run `go mod download` in %s:\n%s",
+						c.name, fixtureDir, out)
+				}
+				t.Fatalf("%s: `go %s` failed:\n%s", c.name, strings.Join(args, " "), out)
+			}
+		})
+	}
+}
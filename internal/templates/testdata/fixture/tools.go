@@ -0,0 +1,38 @@
+//go:build tools
+
+// Package fixture pins the dependencies the generated projects are compiled against.
+// Refresh it with `go get -u ./... && go mod tidy` from this folder.
+package fixture
+
+import (
+	_ "github.com/XSAM/otelsql"
+	_ "github.com/gin-gonic/gin"
+	_ "github.com/glebarez/sqlite"
+	_ "github.com/go-sql-driver/mysql"
+	_ "github.com/gofiber/contrib/otelfiber/v2"
+	_ "github.com/gofiber/fiber/v2"
+	_ "github.com/golang-jwt/jwt/v5"
+	_ "github.com/gorilla/mux"
+	_ "github.com/jmoiron/sqlx"
+	_ "github.com/lib/pq"
+	_ "github.com/prometheus/client_golang/prometheus/promhttp"
+	_ "github.com/rs/zerolog"
+	_ "github.com/stretchr/testify/require"
+	_ "go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	_ "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	_ "go.opentelemetry.io/otel"
+	_ "go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	_ "go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	_ "go.opentelemetry.io/otel/exporters/prometheus"
+	_ "go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	_ "go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	_ "go.opentelemetry.io/otel/sdk/metric"
+	_ "go.opentelemetry.io/otel/sdk/trace"
+	_ "go.uber.org/mock/gomock"
+	_ "go.uber.org/zap"
+	_ "gorm.io/driver/mysql"
+	_ "gorm.io/driver/postgres"
+	_ "gorm.io/gorm"
+	_ "gorm.io/plugin/opentelemetry/tracing"
+	_ "modernc.org/sqlite"
+)
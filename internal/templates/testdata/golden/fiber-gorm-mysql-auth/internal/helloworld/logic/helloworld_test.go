@@ -0,0 +1,125 @@
+package logic
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"example/internal/helloworld/logicerrors"
+	"example/internal/helloworld/repositoryerrors"
+	"example/internal/models"
+)
+
+var errUnexpected = errors.New("unexpected error")
+
+// repoFuncs lets every test decide what the repository returns
+type repoFuncs struct {
+	SaveGreetedUserFunc    func(ctx context.Context, user *models.User) error
+	GetUserFunc            func(ctx context.Context, name string) (models.User, error)
+	GetAllGreetedUsersFunc func(ctx context.Context) ([]models.User, error)
+}
+
+func (f *repoFuncs) SaveGreetedUser(ctx context.Context, user *models.User) error {
+	return f.SaveGreetedUserFunc(ctx, user)
+}
+
+func (f *repoFuncs) GetUser(ctx context.Context, name string) (models.User, error) {
+	return f.GetUserFunc(ctx, name)
+}
+
+func (f *repoFuncs) GetAllGreetedUsers(ctx context.Context) ([]models.User, error) {
+	return f.GetAllGreetedUsersFunc(ctx)
+}
+
+func newRepo(t *testing.T, funcs repoFuncs) HelloWorldRepository {
+	return &funcs
+}
+
+func TestGreet(t *testing.T) {
+	tests := []struct {
+		name         string
+		saveUser     bool
+		saveErr      error
+		wantSaved    bool
+		wantGreeting string
+		wantErr      error
+	}{
+		{name: "greets without saving", wantGreeting: "Hello, gopher!"},
+		{name: "saves the user when asked to", saveUser: true, wantSaved: true, wantGreeting: "Hello, gopher!"},
+		{name: "fails when the user can't be saved", saveUser: true, saveErr: errUnexpected, wantSaved: true, wantErr: errUnexpected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var saved bool
+			logic := NewHelloWorldLogic(newRepo(t, repoFuncs{
+				SaveGreetedUserFunc: func(context.Context, *models.User) error {
+					saved = true
+					return tt.saveErr
+				},
+			}))
+
+			greeting, err := logic.Greet(context.Background(), &models.User{Name: "gopher"}, tt.saveUser)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if greeting != tt.wantGreeting {
+				t.Fatalf("expected greeting %q, got %q", tt.wantGreeting, greeting)
+			}
+			if saved != tt.wantSaved {
+				t.Fatalf("expected saved to be %t, got %t", tt.wantSaved, saved)
+			}
+		})
+	}
+}
+
+func TestGetUserByName(t *testing.T) {
+	tests := []struct {
+		name     string
+		repoErr  error
+		wantUser models.User
+		wantErr  error
+	}{
+		{name: "existing user", wantUser: models.User{ID: 1, Name: "gopher"}},
+		{name: "missing user", repoErr: repositoryerrors.ErrRecordNotFound, wantErr: logicerrors.ErrUserDoesNotExist},
+		{name: "unexpected error", repoErr: errUnexpected, wantErr: errUnexpected},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := NewHelloWorldLogic(newRepo(t, repoFuncs{
+				GetUserFunc: func(_ context.Context, name string) (models.User, error) {
+					if tt.repoErr != nil {
+						return models.User{}, tt.repoErr
+					}
+					return models.User{ID: 1, Name: name}, nil
+				},
+			}))
+
+			user, err := logic.GetUserByName(context.Background(), "gopher")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if user != tt.wantUser {
+				t.Fatalf("expected user %+v, got %+v", tt.wantUser, user)
+			}
+		})
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	want := []models.User{{ID: 1, Name: "alice"}, {ID: 2, Name: "bob"}}
+	logic := NewHelloWorldLogic(newRepo(t, repoFuncs{
+		GetAllGreetedUsersFunc: func(context.Context) ([]models.User, error) {
+			return want, nil
+		},
+	}))
+
+	users, err := logic.ListUsers(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(users) != len(want) {
+		t.Fatalf("expected %d users, got %d", len(want), len(users))
+	}
+}
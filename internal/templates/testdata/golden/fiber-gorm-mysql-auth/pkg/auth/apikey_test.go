@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestAPIKeys(t *testing.T) {
+	keys, err := ParseAPIKeys("billing:5f4dcc3b, reports:7c6a180b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		key         string
+		wantSubject string
+		wantErr     error
+	}{
+		{key: "5f4dcc3b", wantSubject: "billing"},
+		{key: "7c6a180b", wantSubject: "reports"},
+		{key: "", wantErr: ErrNoCredentials},
+		{key: "billing", wantErr: ErrInvalidCredentials},
+		{key: "5f4dcc3", wantErr: ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		principal, err := keys.Verify(tt.key)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%q: expected error %v, got %v", tt.key, tt.wantErr, err)
+		}
+		if principal.Subject != tt.wantSubject {
+			t.Errorf("%q: expected subject %q, got %q", tt.key, tt.wantSubject, principal.Subject)
+		}
+	}
+}
+
+func TestParseAPIKeysRejectsKeysWithoutName(t *testing.T) {
+	for _, s := range []string{"5f4dcc3b", ":5f4dcc3b", "billing:"} {
+		if _, err := ParseAPIKeys(s); err == nil {
+			t.Errorf("%q: expected an error, got nil", s)
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package middlewares
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"example/pkg/auth"
+	"example/pkg/httphelpers"
+)
+
+// RequireJWT answers with a 401 unless the Authorization header has a bearer token verifier accepts,
+// the Principal of the token is added to the user context
+func RequireJWT(verifier *auth.JWTVerifier) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, err := auth.BearerToken(c.Get(fiber.HeaderAuthorization))
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+			httphelpers.StatusUnauthorizedResponse(c)
+			return nil
+		}
+		principal, err := verifier.Verify(token)
+		if err != nil {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+			httphelpers.StatusUnauthorizedResponse(c)
+			return nil
+		}
+		c.SetUserContext(auth.NewContext(c.UserContext(), principal))
+		return c.Next()
+	}
+}
+
+// RequireAPIKey answers with a 401 unless the X-API-Key header has one of keys,
+// the Principal named after the key is added to the user context
+func RequireAPIKey(keys *auth.APIKeys) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, err := keys.Verify(c.Get(auth.APIKeyHeader))
+		if err != nil {
+			httphelpers.StatusUnauthorizedResponse(c)
+			return nil
+		}
+		c.SetUserContext(auth.NewContext(c.UserContext(), principal))
+		return c.Next()
+	}
+}
+
+// RequireScope answers with a 403 unless the Principal RequireJWT added has scope,
+// and with a 401 when there is no Principal
+func RequireScope(scope string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		principal, ok := auth.FromContext(c.UserContext())
+		if !ok {
+			httphelpers.StatusUnauthorizedResponse(c)
+			return nil
+		}
+		if !principal.HasScope(scope) {
+			httphelpers.StatusForbiddenResponse(c)
+			return nil
+		}
+		return c.Next()
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"gorm.io/gorm"
+
+	"gorm.io/driver/postgres"
+
+	"github.com/gofiber/fiber/v2"
+
+	"example/internal/health"
+	"example/internal/helloworld/handlers"
+	"example/internal/helloworld/logic"
+	"example/internal/helloworld/repo"
+	"example/pkg/logging"
+	"example/pkg/middlewares"
+	"example/pkg/taskutils"
+
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout is how long the requests in flight have to finish once a shutdown starts,
+// it is below the 30 seconds kubernetes waits before killing the pod
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	var err error
+
+	// LOG_FORMAT is json, the default, or text, LOG_LEVEL is debug, info, the default, warn or error
+	logger, err := logging.New(os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.SetDefault(logger)
+	taskutils.SetLogger(logger)
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "user=foo password=bar dbname=foobar host=localhost port=5432 sslmode=disable"
+	}
+	conn := postgres.Open(dsn)
+
+	db, err := gorm.Open(conn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// the readiness probe pings, and the shutdown closes, the connection pool gorm runs on
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	helloWorldRepo := repo.NewGormRepo(db)
+
+	helloWorldLogic := logic.NewHelloWorldLogic(helloWorldRepo)
+	helloWorldHandler := handlers.NewHelloWorldHandler(helloWorldLogic)
+	healthHandler := health.NewHealthHandler(sqlDB.PingContext)
+
+	r := fiber.New(fiber.Config{
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	})
+	r.Use(middlewares.RequestLogger(logger), middlewares.RecoverPanic())
+
+	makeRoutes(r, helloWorldHandler, healthHandler)
+
+	// SIGINT (ctrl+c) and SIGTERM (docker stop, kubernetes) start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- r.Listen(":4000")
+	}()
+
+	select {
+	case err = <-serverErr:
+		// the server stopped on its own, e.g. the port is taken, there is nothing to shut down
+	case <-ctx.Done():
+		logger.Info("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = r.ShutdownWithContext(shutdownCtx)
+		cancel()
+	}
+	// a second signal kills the process right away
+	stop()
+
+	// the tasks started with taskutils.BackgroundTask may still use the database
+	taskutils.WaitAll()
+	if closeErr := sqlDB.Close(); closeErr != nil {
+		logger.Error("closing the database failed", "error", closeErr)
+	}
+
+	if err != nil {
+		logger.Error("the server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func makeRoutes(r *fiber.App, handler *handlers.FiberHelloWorldHandler, healthHandler *health.FiberHealthHandler) {
+	r.Get("/healthz", healthHandler.Liveness())
+	r.Get("/readyz", healthHandler.Readiness())
+	r.Get("/version", healthHandler.Version())
+
+	v1 := r.Group("/v1")
+	{
+		helloworld := v1.Group("/helloworld")
+		{
+			helloworld.Post("", handler.Greet())
+			helloworld.Get("", handler.ListUsers())
+			helloworld.Get("/:name", handler.GetUserByName())
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+type FiberHealthHandler struct {
+	pingers []Pinger
+}
+
+func NewHealthHandler(pingers ...Pinger) *FiberHealthHandler {
+	return &FiberHealthHandler{
+		pingers: pingers,
+	}
+}
+
+// get /healthz, the server is alive as long as it answers
+func (h *FiberHealthHandler) Liveness() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": "ok"})
+	}
+}
+
+// get /readyz, the server is ready to take traffic once its dependencies answer
+func (h *FiberHealthHandler) Readiness() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		if err := ready(c.UserContext(), h.pingers); err != nil {
+			return c.Status(http.StatusServiceUnavailable).JSON(fiber.Map{"status": "unavailable"})
+		}
+		return c.Status(http.StatusOK).JSON(fiber.Map{"status": "ready"})
+	}
+}
+
+// get /version
+func (h *FiberHealthHandler) Version() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		return c.Status(http.StatusOK).JSON(ReadBuildInfo())
+	}
+}
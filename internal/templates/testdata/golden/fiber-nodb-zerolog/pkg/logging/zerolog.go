@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/rs/zerolog"
+)
+
+// New returns a Logger that writes to stderr, JSON entries when format is json or empty,
+// or text ones when it's text. level is debug, info, warn or error, info when empty
+func New(format, level string) (Logger, error) {
+	lvl := zerolog.InfoLevel
+	if level != "" {
+		var err error
+		lvl, err = zerolog.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("unknown log level %q, use debug, info, warn or error", level)
+		}
+	}
+
+	var w io.Writer
+	switch format {
+	case "", "json":
+		w = os.Stderr
+	case "text":
+		w = zerolog.ConsoleWriter{Out: os.Stderr}
+	default:
+		return nil, fmt.Errorf("unknown log format %q, use json or text", format)
+	}
+
+	return zerologLogger{l: zerolog.New(w).Level(lvl).With().Timestamp().Logger()}, nil
+}
+
+func newDefault() Logger {
+	return zerologLogger{l: zerolog.New(os.Stderr).Level(zerolog.InfoLevel).With().Timestamp().Logger()}
+}
+
+type zerologLogger struct {
+	l zerolog.Logger
+}
+
+// args are given to Fields, which takes them as alternating keys and values
+func (z zerologLogger) Debug(msg string, args ...any) {
+	z.l.Debug().Fields(args).Msg(msg)
+}
+
+func (z zerologLogger) Info(msg string, args ...any) {
+	z.l.Info().Fields(args).Msg(msg)
+}
+
+func (z zerologLogger) Warn(msg string, args ...any) {
+	z.l.Warn().Fields(args).Msg(msg)
+}
+
+func (z zerologLogger) Error(msg string, args ...any) {
+	z.l.Error().Fields(args).Msg(msg)
+}
+
+func (z zerologLogger) With(args ...any) Logger {
+	return zerologLogger{l: z.l.With().Fields(args).Logger()}
+}
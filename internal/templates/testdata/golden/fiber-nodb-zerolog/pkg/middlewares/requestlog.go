@@ -0,0 +1,69 @@
+package middlewares
+
+import (
+	"errors"
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+
+	"example/pkg/logging"
+)
+
+// RequestLogger logs every request once it's answered. Its ID is the one of the X-Request-ID header, or a new one,
+// it's answered in the same header and added to the entries of the logger of the user context
+func RequestLogger(logger logging.Logger) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		start := time.Now()
+		id := logging.RequestIDOrNew(c.Get(logging.RequestIDHeader))
+		c.Set(logging.RequestIDHeader, id)
+
+		reqLogger := logger.With("request_id", id)
+		ctx := logging.WithRequestID(c.UserContext(), id)
+		c.SetUserContext(logging.NewContext(ctx, reqLogger))
+
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		if err != nil {
+			// the error handler of the app answers once the middlewares returned
+			status = http.StatusInternalServerError
+			var fiberErr *fiber.Error
+			if errors.As(err, &fiberErr) {
+				status = fiberErr.Code
+			}
+		}
+		args := []any{
+			"method", c.Method(),
+			"path", c.Path(),
+			"status", status,
+			"duration", time.Since(start),
+		}
+		// httphelpers.StatusInternalServerErrorResponse stores the error in the locals
+		if handlerErr, ok := c.Locals("error").(error); ok {
+			args = append(args, "error", handlerErr)
+		} else if err != nil {
+			args = append(args, "error", err)
+		}
+		if status >= http.StatusInternalServerError {
+			reqLogger.Error("request", args...)
+		} else {
+			reqLogger.Info("request", args...)
+		}
+		return err
+	}
+}
+
+// RecoverPanic answers with a 500 when a handler panics, and logs the panic with its stack trace
+func RecoverPanic() fiber.Handler {
+	return func(c *fiber.Ctx) (err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logging.FromContext(c.UserContext()).Error("panic recovered", "panic", r, "stack", string(debug.Stack()))
+				err = fiber.ErrInternalServerError
+			}
+		}()
+		return c.Next()
+	}
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"github.com/XSAM/otelsql"
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/gofiber/contrib/otelfiber/v2"
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/adaptor"
+
+	"example/internal/health"
+	"example/internal/helloworld/handlers"
+	"example/internal/helloworld/logic"
+	"example/internal/helloworld/repo"
+	"example/pkg/taskutils"
+	"example/pkg/telemetry"
+
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout is how long the requests in flight have to finish once a shutdown starts,
+// it is below the 30 seconds kubernetes waits before killing the pod
+const shutdownTimeout = 10 * time.Second
+
+// serviceName names the traces and metrics, OTEL_SERVICE_NAME replaces it
+const serviceName = "example"
+
+func main() {
+	var err error
+
+	// the traces and metrics are written to stdout, unless OTEL_EXPORTER_OTLP_ENDPOINT points to a collector
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), serviceName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "username:password@/databasename?parseTime=true"
+	}
+
+	// the queries are traced, and the connection pool is reported as metrics
+	sqlDB, err := otelsql.Open("mysql", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = otelsql.RegisterDBStatsMetrics(sqlDB)
+	if err != nil {
+		log.Fatal(err)
+	}
+	db := sqlx.NewDb(sqlDB, "mysql")
+	// like sqlx.Connect, the server does not start without a database
+	err = db.Ping()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	helloWorldRepo := repo.NewSqlxRepo(db)
+
+	helloWorldLogic := logic.NewHelloWorldLogic(helloWorldRepo)
+	helloWorldHandler := handlers.NewHelloWorldHandler(helloWorldLogic)
+	healthHandler := health.NewHealthHandler(db.PingContext)
+
+	r := fiber.New(fiber.Config{
+		ReadTimeout:  15 * time.Second,
+		WriteTimeout: 15 * time.Second,
+		IdleTimeout:  60 * time.Second,
+	})
+	r.Use(otelfiber.Middleware())
+
+	makeRoutes(r, helloWorldHandler, healthHandler)
+
+	// SIGINT (ctrl+c) and SIGTERM (docker stop, kubernetes) start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- r.Listen(":4000")
+	}()
+
+	select {
+	case err = <-serverErr:
+		// the server stopped on its own, e.g. the port is taken, there is nothing to shut down
+	case <-ctx.Done():
+		log.Print("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = r.ShutdownWithContext(shutdownCtx)
+		cancel()
+	}
+	// a second signal kills the process right away
+	stop()
+
+	// the tasks started with taskutils.BackgroundTask may still use the database
+	taskutils.WaitAll()
+	if closeErr := db.Close(); closeErr != nil {
+		log.Print(closeErr)
+	}
+	// the spans and metrics that are left are exported before exiting
+	telemetryCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	if telemetryErr := shutdownTelemetry(telemetryCtx); telemetryErr != nil {
+		log.Print(telemetryErr)
+	}
+	cancel()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func makeRoutes(r *fiber.App, handler *handlers.FiberHelloWorldHandler, healthHandler *health.FiberHealthHandler) {
+	r.Get("/healthz", healthHandler.Liveness())
+	r.Get("/readyz", healthHandler.Readiness())
+	r.Get("/version", healthHandler.Version())
+	r.Get("/metrics", adaptor.HTTPHandler(telemetry.MetricsHandler()))
+
+	v1 := r.Group("/v1")
+	{
+		helloworld := v1.Group("/helloworld")
+		{
+			helloworld.Post("", handler.Greet())
+			helloworld.Get("", handler.ListUsers())
+			helloworld.Get("/:name", handler.GetUserByName())
+		}
+	}
+}
@@ -0,0 +1,140 @@
+// Package telemetry sets up OpenTelemetry, traces and metrics are exported with OTLP, or written to stdout
+// when no collector is configured, and metrics are served to Prometheus too
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
+	"go.opentelemetry.io/otel/exporters/stdout/stdoutmetric"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// The exporters OTEL_TRACES_EXPORTER and OTEL_METRICS_EXPORTER choose from
+const (
+	exporterOtlp    = "otlp"
+	exporterConsole = "console"
+	exporterNone    = "none"
+)
+
+// registry holds the metrics MetricsHandler serves, along with the ones of the go runtime and the process
+var registry = func() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(collectors.NewGoCollector(), collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}))
+	return r
+}()
+
+// Setup installs the global tracer provider, meter provider and propagator, call the returned shutdown
+// before exiting so the spans and metrics that are left get exported.
+//
+// OTEL_TRACES_EXPORTER and OTEL_METRICS_EXPORTER are otlp, console or none. When unset, it's otlp if an OTLP endpoint
+// is set, e.g. with OTEL_EXPORTER_OTLP_ENDPOINT, and console, which writes to stdout, otherwise.
+// The OTLP exporters read the rest of the OTEL_EXPORTER_OTLP_* variables, and OTEL_SERVICE_NAME replaces serviceName
+func Setup(ctx context.Context, serviceName string) (shutdown func(context.Context) error, err error) {
+	res, err := resource.New(ctx,
+		resource.WithAttributes(attribute.String("service.name", serviceName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	traceExporter, err := newTraceExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	tracerOpts := []sdktrace.TracerProviderOption{sdktrace.WithResource(res)}
+	if traceExporter != nil {
+		tracerOpts = append(tracerOpts, sdktrace.WithBatcher(traceExporter))
+	}
+	tracerProvider := sdktrace.NewTracerProvider(tracerOpts...)
+
+	// the Prometheus exporter is a reader, the metrics are collected every time /metrics is scraped
+	promExporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+	if err != nil {
+		return nil, err
+	}
+	meterOpts := []sdkmetric.Option{sdkmetric.WithResource(res), sdkmetric.WithReader(promExporter)}
+	metricExporter, err := newMetricExporter(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if metricExporter != nil {
+		meterOpts = append(meterOpts, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter)))
+	}
+	meterProvider := sdkmetric.NewMeterProvider(meterOpts...)
+
+	otel.SetTracerProvider(tracerProvider)
+	otel.SetMeterProvider(meterProvider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	return func(ctx context.Context) error {
+		return errors.Join(tracerProvider.Shutdown(ctx), meterProvider.Shutdown(ctx))
+	}, nil
+}
+
+// MetricsHandler serves the metrics in the Prometheus format, for GET /metrics
+func MetricsHandler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// newTraceExporter returns the exporter of OTEL_TRACES_EXPORTER, nil for none
+func newTraceExporter(ctx context.Context) (sdktrace.SpanExporter, error) {
+	name, err := exporter(os.Getenv("OTEL_TRACES_EXPORTER"), "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case exporterOtlp:
+		return otlptracehttp.New(ctx)
+	case exporterConsole:
+		return stdouttrace.New()
+	}
+	return nil, nil
+}
+
+// newMetricExporter returns the exporter of OTEL_METRICS_EXPORTER, nil for none
+func newMetricExporter(ctx context.Context) (sdkmetric.Exporter, error) {
+	name, err := exporter(os.Getenv("OTEL_METRICS_EXPORTER"), "OTEL_EXPORTER_OTLP_METRICS_ENDPOINT")
+	if err != nil {
+		return nil, err
+	}
+	switch name {
+	case exporterOtlp:
+		return otlpmetrichttp.New(ctx)
+	case exporterConsole:
+		return stdoutmetric.New()
+	}
+	return nil, nil
+}
+
+// exporter returns the exporter name chooses, when empty otlp if OTEL_EXPORTER_OTLP_ENDPOINT or endpointVar are set,
+// console otherwise
+func exporter(name, endpointVar string) (string, error) {
+	switch name {
+	case exporterOtlp, exporterConsole, exporterNone:
+		return name, nil
+	case "":
+		if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") != "" || os.Getenv(endpointVar) != "" {
+			return exporterOtlp, nil
+		}
+		return exporterConsole, nil
+	}
+	return "", fmt.Errorf("unknown exporter %q, use otlp, console or none", name)
+}
@@ -0,0 +1,88 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"example/internal/helloworld/repositoryerrors"
+	"example/internal/models"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// dbtx is satisfied by both *sqlx.DB and *sqlx.Tx, so the repository can also run inside a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	GetContext(ctx context.Context, dest any, query string, args ...any) error
+	SelectContext(ctx context.Context, dest any, query string, args ...any) error
+}
+
+type sqlxRepo struct {
+	db dbtx
+}
+
+func NewSqlxRepo(db *sqlx.DB) *sqlxRepo {
+	return &sqlxRepo{
+		db: db,
+	}
+}
+
+func (r *sqlxRepo) SaveGreetedUser(ctx context.Context, user *models.User) error {
+
+	query := `INSERT INTO users (name)
+				VALUES($1)
+				ON CONFLICT(name) DO NOTHING`
+
+	_, err := r.db.ExecContext(ctx, query, user.Name)
+	if err != nil {
+		return err
+	}
+
+	// not every DBMS supports RETURNING, so the saved user is read back instead
+	saved, err := r.GetUser(ctx, user.Name)
+	if err != nil {
+		return err
+	}
+	*user = saved
+
+	return nil
+}
+
+func (r *sqlxRepo) GetUser(ctx context.Context, name string) (models.User, error) {
+	var user models.User
+
+	query := `SELECT name, id, registered_at FROM users
+				WHERE name = $1`
+
+	// db.Get loads the first element into dest
+	err := r.db.GetContext(ctx, &user, query, name)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.User{}, repositoryerrors.ErrRecordNotFound
+		default:
+			return models.User{}, err
+		}
+	}
+
+	return user, nil
+}
+
+func (r *sqlxRepo) GetAllGreetedUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	query := `SELECT name, id, registered_at
+				FROM users`
+
+	// db.Select loads a slice of elements into dest
+	err := r.db.SelectContext(ctx, &users, query)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return []models.User{}, nil
+		default:
+			return []models.User{}, err
+		}
+	}
+
+	return users, nil
+}
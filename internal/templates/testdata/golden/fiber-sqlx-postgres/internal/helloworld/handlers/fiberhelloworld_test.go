@@ -0,0 +1,227 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+
+	"example/internal/helloworld/logicerrors"
+	"example/internal/models"
+)
+
+// logicFuncs lets every test decide what the logic layer returns
+type logicFuncs struct {
+	GreetFunc         func(ctx context.Context, user *models.User, saveUser bool) (string, error)
+	GetUserByNameFunc func(ctx context.Context, name string) (models.User, error)
+	ListUsersFunc     func(ctx context.Context) ([]models.User, error)
+}
+
+func (f *logicFuncs) Greet(ctx context.Context, user *models.User, saveUser bool) (string, error) {
+	return f.GreetFunc(ctx, user, saveUser)
+}
+
+func (f *logicFuncs) GetUserByName(ctx context.Context, name string) (models.User, error) {
+	return f.GetUserByNameFunc(ctx, name)
+}
+
+func (f *logicFuncs) ListUsers(ctx context.Context) ([]models.User, error) {
+	return f.ListUsersFunc(ctx)
+}
+
+func newLogic(t *testing.T, funcs logicFuncs) HelloWorldLogic {
+	return &funcs
+}
+
+// serve sends a request through an app with the same routes as cmd/example, without the /v1 prefix
+func serve(t *testing.T, logic HelloWorldLogic, method, target, body string) (int, string) {
+	t.Helper()
+
+	handler := NewHelloWorldHandler(logic)
+	app := fiber.New()
+	app.Post("/helloworld", handler.Greet())
+	app.Get("/helloworld", handler.ListUsers())
+	app.Get("/helloworld/:name", handler.GetUserByName())
+
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	// -1 disables the timeout, so slow machines don't make the tests flaky
+	resp, err := app.Test(req, -1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return resp.StatusCode, string(respBody)
+}
+
+func TestGreet(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		body       string
+		greetErr   error
+		wantStatus int
+		wantCalled bool
+		wantSave   bool
+		wantBody   string
+	}{
+		{
+			name:       "greets without saving by default",
+			target:     "/helloworld",
+			body:       `{"name":"gopher"}`,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+			wantBody:   "Hello, gopher!",
+		},
+		{
+			name:       "saves the user when asked to",
+			target:     "/helloworld?save=true",
+			body:       `{"name":"gopher"}`,
+			wantStatus: http.StatusOK,
+			wantCalled: true,
+			wantSave:   true,
+			wantBody:   "Hello, gopher!",
+		},
+		{
+			name:       "rejects an invalid save parameter",
+			target:     "/helloworld?save=maybe",
+			body:       `{"name":"gopher"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "rejects a bad JSON body",
+			target:     "/helloworld",
+			body:       `{"name":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "rejects more than one JSON value",
+			target:     "/helloworld",
+			body:       `{"name":"gopher"}{"name":"gopher"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "fails when the logic fails",
+			target:     "/helloworld",
+			body:       `{"name":"gopher"}`,
+			greetErr:   errors.New("unexpected error"),
+			wantStatus: http.StatusInternalServerError,
+			wantCalled: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				called bool
+				saved  bool
+			)
+			logic := newLogic(t, logicFuncs{
+				GreetFunc: func(_ context.Context, user *models.User, saveUser bool) (string, error) {
+					called, saved = true, saveUser
+					if tt.greetErr != nil {
+						return "", tt.greetErr
+					}
+					return "Hello, " + user.Name + "!", nil
+				},
+			})
+
+			status, body := serve(t, logic, http.MethodPost, tt.target, tt.body)
+			if status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if called != tt.wantCalled {
+				t.Fatalf("expected logic to be called: %t, got %t", tt.wantCalled, called)
+			}
+			if saved != tt.wantSave {
+				t.Fatalf("expected save to be %t, got %t", tt.wantSave, saved)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestGetUserByName(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "existing user", wantStatus: http.StatusOK, wantBody: `"name":"gopher"`},
+		{name: "missing user", err: logicerrors.ErrUserDoesNotExist, wantStatus: http.StatusBadRequest, wantBody: "user not found"},
+		{name: "unexpected error", err: errors.New("unexpected error"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var lookedUp string
+			logic := newLogic(t, logicFuncs{
+				GetUserByNameFunc: func(_ context.Context, name string) (models.User, error) {
+					lookedUp = name
+					if tt.err != nil {
+						return models.User{}, tt.err
+					}
+					return models.User{ID: 1, Name: name}, nil
+				},
+			})
+
+			status, body := serve(t, logic, http.MethodGet, "/helloworld/gopher", "")
+			if lookedUp != "gopher" {
+				t.Fatalf("expected to look up %q, got %q", "gopher", lookedUp)
+			}
+			if status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
+
+func TestListUsers(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "lists users", wantStatus: http.StatusOK, wantBody: `"name":"gopher"`},
+		{name: "unexpected error", err: errors.New("unexpected error"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			logic := newLogic(t, logicFuncs{
+				ListUsersFunc: func(context.Context) ([]models.User, error) {
+					if tt.err != nil {
+						return nil, tt.err
+					}
+					return []models.User{{ID: 1, Name: "gopher"}}, nil
+				},
+			})
+
+			status, body := serve(t, logic, http.MethodGet, "/helloworld", "")
+			if status != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Fatalf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type GinHealthHandler struct {
+	pingers []Pinger
+}
+
+func NewHealthHandler(pingers ...Pinger) *GinHealthHandler {
+	return &GinHealthHandler{
+		pingers: pingers,
+	}
+}
+
+// get /healthz, the server is alive as long as it answers
+func (h *GinHealthHandler) Liveness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	}
+}
+
+// get /readyz, the server is ready to take traffic once its dependencies answer
+func (h *GinHealthHandler) Readiness() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if err := ready(c.Request.Context(), h.pingers); err != nil {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"status": "unavailable"})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"status": "ready"})
+	}
+}
+
+// get /version
+func (h *GinHealthHandler) Version() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, ReadBuildInfo())
+	}
+}
@@ -0,0 +1,142 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+
+	"gorm.io/driver/postgres"
+
+	"gorm.io/gorm"
+
+	"example/internal/helloworld/repositoryerrors"
+	"example/internal/models"
+)
+
+// newTestRepo connects to DB_TEST_DSN when it is set (for example, the database started by your compose file),
+// otherwise it falls back to an in-memory SQLite database, gorm takes care of the dialect differences.
+// The repository runs in a transaction that is rolled back when the test ends, so the database is left untouched
+func newTestRepo(t *testing.T) *gormRepo {
+	t.Helper()
+
+	conn := sqlite.Open(":memory:")
+	if dsn := os.Getenv("DB_TEST_DSN"); dsn != "" {
+		conn = postgres.Open(dsn)
+	}
+
+	db, err := gorm.Open(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection to an in-memory SQLite database gets its own database
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		t.Fatal(err)
+	}
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatal(tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+
+	return NewGormRepo(tx)
+}
+
+func TestSaveGreetedUser(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+
+	user := models.User{Name: "gopher"}
+	err := r.SaveGreetedUser(ctx, &user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if user.ID == 0 {
+		t.Fatal("expected user ID to be set")
+	}
+
+	// greeting the same user twice must not create a new record
+	again := models.User{Name: "gopher"}
+	err = r.SaveGreetedUser(ctx, &again)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if again.ID != user.ID {
+		t.Fatalf("expected ID %d, got %d", user.ID, again.ID)
+	}
+}
+
+func TestGetUser(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+
+	saved := models.User{Name: "gopher"}
+	if err := r.SaveGreetedUser(ctx, &saved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		lookup  string
+		wantErr error
+	}{
+		{name: "existing user", lookup: "gopher"},
+		{name: "missing user", lookup: "nobody", wantErr: repositoryerrors.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := r.GetUser(ctx, tt.lookup)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if user.ID != saved.ID || user.Name != saved.Name {
+				t.Fatalf("expected %+v, got %+v", saved, user)
+			}
+		})
+	}
+}
+
+func TestGetAllGreetedUsers(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+
+	// the database behind DB_TEST_DSN may already hold users, so only the ones saved here are looked for
+	names := []string{"alice", "bob"}
+	for _, name := range names {
+		if err := r.SaveGreetedUser(ctx, &models.User{Name: name}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	users, err := r.GetAllGreetedUsers(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found := make(map[string]bool, len(users))
+	for _, user := range users {
+		found[user.Name] = true
+	}
+	for _, name := range names {
+		if !found[name] {
+			t.Fatalf("expected %s among the greeted users", name)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package middlewares
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"example/pkg/auth"
+	"example/pkg/httphelpers"
+)
+
+// RequireJWT answers with a 401 unless the Authorization header has a bearer token verifier accepts,
+// the Principal of the token is added to the request context
+func RequireJWT(verifier *auth.JWTVerifier) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, err := auth.BearerToken(c.GetHeader("Authorization"))
+		if err != nil {
+			c.Header("WWW-Authenticate", "Bearer")
+			httphelpers.StatusUnauthorizedResponse(c)
+			c.Abort()
+			return
+		}
+		principal, err := verifier.Verify(token)
+		if err != nil {
+			c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+			httphelpers.StatusUnauthorizedResponse(c)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireAPIKey answers with a 401 unless the X-API-Key header has one of keys,
+// the Principal named after the key is added to the request context
+func RequireAPIKey(keys *auth.APIKeys) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, err := keys.Verify(c.GetHeader(auth.APIKeyHeader))
+		if err != nil {
+			httphelpers.StatusUnauthorizedResponse(c)
+			c.Abort()
+			return
+		}
+		c.Request = c.Request.WithContext(auth.NewContext(c.Request.Context(), principal))
+		c.Next()
+	}
+}
+
+// RequireScope answers with a 403 unless the Principal RequireJWT added has scope,
+// and with a 401 when there is no Principal
+func RequireScope(scope string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		principal, ok := auth.FromContext(c.Request.Context())
+		if !ok {
+			httphelpers.StatusUnauthorizedResponse(c)
+			c.Abort()
+			return
+		}
+		if !principal.HasScope(scope) {
+			httphelpers.StatusForbiddenResponse(c)
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
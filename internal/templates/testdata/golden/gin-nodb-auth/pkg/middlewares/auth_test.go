@@ -0,0 +1,194 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+
+	"example/pkg/auth"
+)
+
+// serve sends req through mws to a handler that answers with the subject of the principal
+func serve(t *testing.T, req *http.Request, mws ...gin.HandlerFunc) (int, http.Header, string) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	r := gin.New()
+	r.GET("/", append(mws, func(c *gin.Context) {
+		principal, _ := auth.FromContext(c.Request.Context())
+		c.String(http.StatusOK, principal.Subject)
+	})...)
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+
+	return w.Code, w.Header(), w.Body.String()
+}
+
+// newSecret generates the HS256 secret of a test
+func newSecret(t *testing.T) []byte {
+	t.Helper()
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+// bearer returns the Authorization header of a token for alice, with scope, that expires at exp
+func bearer(t *testing.T, secret []byte, scope string, exp time.Time) string {
+	t.Helper()
+
+	claims := jwt.MapClaims{"sub": "alice", "scope": scope, "exp": exp.Unix()}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(secret)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return "Bearer " + token
+}
+
+func TestRequireJWT(t *testing.T) {
+	secret := newSecret(t)
+	verifier, err := auth.NewJWTVerifier(auth.JWTConfig{Secret: secret})
+	if err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name          string
+		authorization string
+		scope         string
+		wantStatus    int
+		wantBody      string
+	}{
+		{
+			name:       "answers 401 without a token",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `"error":"unauthorized"`,
+		},
+		{
+			name:          "answers 401 to a token signed with another secret",
+			authorization: bearer(t, newSecret(t), "", later),
+			wantStatus:    http.StatusUnauthorized,
+			wantBody:      `"error":"unauthorized"`,
+		},
+		{
+			name:          "answers 401 to an expired token",
+			authorization: bearer(t, secret, "", time.Now().Add(-time.Minute)),
+			wantStatus:    http.StatusUnauthorized,
+			wantBody:      `"error":"unauthorized"`,
+		},
+		{
+			name:          "passes the principal of the token to the handler",
+			authorization: bearer(t, secret, "", later),
+			wantStatus:    http.StatusOK,
+			wantBody:      "alice",
+		},
+		{
+			name:          "passes tokens with the required scope",
+			authorization: bearer(t, secret, "users:read users:write", later),
+			scope:         "users:write",
+			wantStatus:    http.StatusOK,
+			wantBody:      "alice",
+		},
+		{
+			name:          "answers 403 to tokens without the required scope",
+			authorization: bearer(t, secret, "users:read", later),
+			scope:         "users:write",
+			wantStatus:    http.StatusForbidden,
+			wantBody:      `"error":"forbidden"`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			var (
+				status int
+				header http.Header
+				body   string
+			)
+			if tt.scope == "" {
+				status, header, body = serve(t, req, RequireJWT(verifier))
+			} else {
+				status, header, body = serve(t, req, RequireJWT(verifier), RequireScope(tt.scope))
+			}
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+			if status == http.StatusUnauthorized && !strings.HasPrefix(header.Get("WWW-Authenticate"), "Bearer") {
+				t.Errorf("expected a Bearer challenge, got %q", header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestRequireScopeWithoutPrincipal(t *testing.T) {
+	status, _, _ := serve(t, httptest.NewRequest(http.MethodGet, "/", nil), RequireScope("users:write"))
+	if status != http.StatusUnauthorized {
+		t.Errorf("expected status %d, got %d", http.StatusUnauthorized, status)
+	}
+}
+
+func TestRequireAPIKey(t *testing.T) {
+	keys, err := auth.ParseAPIKeys("billing:5f4dcc3b")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "answers 401 without a key",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `"error":"unauthorized"`,
+		},
+		{
+			name:       "answers 401 to an unknown key",
+			key:        "7c6a180b",
+			wantStatus: http.StatusUnauthorized,
+			wantBody:   `"error":"unauthorized"`,
+		},
+		{
+			name:       "passes the principal of the key to the handler",
+			key:        "5f4dcc3b",
+			wantStatus: http.StatusOK,
+			wantBody:   "billing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.key != "" {
+				req.Header.Set(auth.APIKeyHeader, tt.key)
+			}
+
+			status, _, body := serve(t, req, RequireAPIKey(keys))
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: helloworld.go
+//
+// Generated by this command:
+//
+//	mockgen -source=helloworld.go -destination=../mocks/repository.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "example/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHelloWorldRepository is a mock of HelloWorldRepository interface.
+type MockHelloWorldRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockHelloWorldRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockHelloWorldRepositoryMockRecorder is the mock recorder for MockHelloWorldRepository.
+type MockHelloWorldRepositoryMockRecorder struct {
+	mock *MockHelloWorldRepository
+}
+
+// NewMockHelloWorldRepository creates a new mock instance.
+func NewMockHelloWorldRepository(ctrl *gomock.Controller) *MockHelloWorldRepository {
+	mock := &MockHelloWorldRepository{ctrl: ctrl}
+	mock.recorder = &MockHelloWorldRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHelloWorldRepository) EXPECT() *MockHelloWorldRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetAllGreetedUsers mocks base method.
+func (m *MockHelloWorldRepository) GetAllGreetedUsers(arg0 context.Context) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAllGreetedUsers", arg0)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAllGreetedUsers indicates an expected call of GetAllGreetedUsers.
+func (mr *MockHelloWorldRepositoryMockRecorder) GetAllGreetedUsers(arg0 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllGreetedUsers", reflect.TypeOf((*MockHelloWorldRepository)(nil).GetAllGreetedUsers), arg0)
+}
+
+// GetUser mocks base method.
+func (m *MockHelloWorldRepository) GetUser(ctx context.Context, name string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, name)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockHelloWorldRepositoryMockRecorder) GetUser(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockHelloWorldRepository)(nil).GetUser), ctx, name)
+}
+
+// SaveGreetedUser mocks base method.
+func (m *MockHelloWorldRepository) SaveGreetedUser(arg0 context.Context, arg1 *models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveGreetedUser", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveGreetedUser indicates an expected call of SaveGreetedUser.
+func (mr *MockHelloWorldRepositoryMockRecorder) SaveGreetedUser(arg0, arg1 any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveGreetedUser", reflect.TypeOf((*MockHelloWorldRepository)(nil).SaveGreetedUser), arg0, arg1)
+}
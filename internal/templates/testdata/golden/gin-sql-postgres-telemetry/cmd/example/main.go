@@ -0,0 +1,131 @@
+package main
+
+import (
+	"github.com/XSAM/otelsql"
+
+	_ "github.com/lib/pq"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"net/http"
+
+	"example/internal/health"
+	"example/internal/helloworld/handlers"
+	"example/internal/helloworld/logic"
+	"example/internal/helloworld/repo"
+	"example/pkg/taskutils"
+	"example/pkg/telemetry"
+
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout is how long the requests in flight have to finish once a shutdown starts,
+// it is below the 30 seconds kubernetes waits before killing the pod
+const shutdownTimeout = 10 * time.Second
+
+// serviceName names the traces and metrics, OTEL_SERVICE_NAME replaces it
+const serviceName = "example"
+
+func main() {
+	var err error
+
+	// the traces and metrics are written to stdout, unless OTEL_EXPORTER_OTLP_ENDPOINT points to a collector
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), serviceName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "user=foo password=bar dbname=foobar host=localhost port=5432 sslmode=disable"
+	}
+
+	// the queries are traced, and the connection pool is reported as metrics
+	db, err := otelsql.Open("postgres", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	_, err = otelsql.RegisterDBStatsMetrics(db)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	helloWorldRepo := repo.NewSqlRepo(db)
+
+	helloWorldLogic := logic.NewHelloWorldLogic(helloWorldRepo)
+	helloWorldHandler := handlers.NewHelloWorldHandler(helloWorldLogic)
+	healthHandler := health.NewHealthHandler(db.PingContext)
+
+	r := gin.Default()
+	r.Use(otelgin.Middleware(serviceName))
+
+	makeRoutes(r, helloWorldHandler, healthHandler)
+
+	srv := &http.Server{
+		Addr:              ":4000",
+		Handler:           r,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	// SIGINT (ctrl+c) and SIGTERM (docker stop, kubernetes) start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		// the server stopped on its own, e.g. the port is taken, there is nothing to shut down
+	case <-ctx.Done():
+		log.Print("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = srv.Shutdown(shutdownCtx)
+		cancel()
+	}
+	// a second signal kills the process right away
+	stop()
+
+	// the tasks started with taskutils.BackgroundTask may still use the database
+	taskutils.WaitAll()
+	if closeErr := db.Close(); closeErr != nil {
+		log.Print(closeErr)
+	}
+	// the spans and metrics that are left are exported before exiting
+	telemetryCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	if telemetryErr := shutdownTelemetry(telemetryCtx); telemetryErr != nil {
+		log.Print(telemetryErr)
+	}
+	cancel()
+
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func makeRoutes(r *gin.Engine, handler *handlers.GinHelloWorldHandler, healthHandler *health.GinHealthHandler) {
+	r.GET("/healthz", healthHandler.Liveness())
+	r.GET("/readyz", healthHandler.Readiness())
+	r.GET("/version", healthHandler.Version())
+	r.GET("/metrics", gin.WrapH(telemetry.MetricsHandler()))
+
+	v1 := r.Group("/v1")
+	{
+		helloworld := v1.Group("/helloworld")
+		{
+			helloworld.POST("", handler.Greet())
+			helloworld.GET("", handler.ListUsers())
+			helloworld.GET("/:name", handler.GetUserByName())
+		}
+	}
+}
@@ -0,0 +1,7 @@
+package repositoryerrors
+
+import "errors"
+
+var (
+	ErrRecordNotFound = errors.New("record not found")
+)
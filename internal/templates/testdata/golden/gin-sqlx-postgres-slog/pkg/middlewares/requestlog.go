@@ -0,0 +1,56 @@
+package middlewares
+
+import (
+	"net/http"
+	"runtime/debug"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"example/pkg/logging"
+)
+
+// RequestLogger logs every request once it's answered. Its ID is the one of the X-Request-ID header, or a new one,
+// it's answered in the same header and added to the entries of the logger of the request context
+func RequestLogger(logger logging.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		id := logging.RequestIDOrNew(c.GetHeader(logging.RequestIDHeader))
+		c.Header(logging.RequestIDHeader, id)
+
+		reqLogger := logger.With("request_id", id)
+		ctx := logging.WithRequestID(c.Request.Context(), id)
+		c.Request = c.Request.WithContext(logging.NewContext(ctx, reqLogger))
+
+		c.Next()
+
+		args := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"status", c.Writer.Status(),
+			"duration", time.Since(start),
+		}
+		// httphelpers.StatusInternalServerErrorResponse adds the error to the context
+		if err := c.Errors.Last(); err != nil {
+			args = append(args, "error", err.Err)
+		}
+		if c.Writer.Status() >= http.StatusInternalServerError {
+			reqLogger.Error("request", args...)
+			return
+		}
+		reqLogger.Info("request", args...)
+	}
+}
+
+// RecoverPanic answers with an empty 500 when a handler panics, and logs the panic with its stack trace
+func RecoverPanic() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		defer func() {
+			if err := recover(); err != nil {
+				logging.FromContext(c.Request.Context()).Error("panic recovered", "panic", err, "stack", string(debug.Stack()))
+				c.AbortWithStatus(http.StatusInternalServerError)
+			}
+		}()
+		c.Next()
+	}
+}
@@ -0,0 +1,80 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// serve sends a request through a router with the same probes as cmd/example
+func serve(t *testing.T, ping Pinger, target string) (int, string) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	handler := NewHealthHandler(ping)
+	r := gin.New()
+	r.GET("/healthz", handler.Liveness())
+	r.GET("/readyz", handler.Readiness())
+	r.GET("/version", handler.Version())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return w.Code, w.Body.String()
+}
+
+func TestProbes(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		pingErr    error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "is alive even when the database is not",
+			target:     "/healthz",
+			pingErr:    errors.New("connection refused"),
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"ok"`,
+		},
+		{
+			name:       "is ready when the database answers",
+			target:     "/readyz",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"ready"`,
+		},
+		{
+			name:       "is not ready when the database does not answer",
+			target:     "/readyz",
+			pingErr:    errors.New("connection refused"),
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `"status":"unavailable"`,
+		},
+		{
+			name:       "tells the version",
+			target:     "/version",
+			wantStatus: http.StatusOK,
+			wantBody:   `"version":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ping := func(context.Context) error { return tt.pingErr }
+
+			status, body := serve(t, ping, tt.target)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"example/internal/models"
+)
+
+// HelloWorldLogic is a hand-written fake of handlers.HelloWorldLogic, set the functions your test needs.
+// Calling a method whose function is not set panics
+type HelloWorldLogic struct {
+	GreetFunc         func(ctx context.Context, user *models.User, saveUser bool) (string, error)
+	GetUserByNameFunc func(ctx context.Context, name string) (models.User, error)
+	ListUsersFunc     func(ctx context.Context) ([]models.User, error)
+}
+
+func (f *HelloWorldLogic) Greet(ctx context.Context, user *models.User, saveUser bool) (string, error) {
+	if f.GreetFunc == nil {
+		panic("mocks: unexpected call to HelloWorldLogic.Greet")
+	}
+	return f.GreetFunc(ctx, user, saveUser)
+}
+
+func (f *HelloWorldLogic) GetUserByName(ctx context.Context, name string) (models.User, error) {
+	if f.GetUserByNameFunc == nil {
+		panic("mocks: unexpected call to HelloWorldLogic.GetUserByName")
+	}
+	return f.GetUserByNameFunc(ctx, name)
+}
+
+func (f *HelloWorldLogic) ListUsers(ctx context.Context) ([]models.User, error) {
+	if f.ListUsersFunc == nil {
+		panic("mocks: unexpected call to HelloWorldLogic.ListUsers")
+	}
+	return f.ListUsersFunc(ctx)
+}
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: gorillamuxhelloworld.go
+//
+// Generated by this command:
+//
+//	mockgen -source=gorillamuxhelloworld.go -destination=../mocks/logic.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	models "example/internal/models"
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHelloWorldLogic is a mock of HelloWorldLogic interface.
+type MockHelloWorldLogic struct {
+	ctrl     *gomock.Controller
+	recorder *MockHelloWorldLogicMockRecorder
+	isgomock struct{}
+}
+
+// MockHelloWorldLogicMockRecorder is the mock recorder for MockHelloWorldLogic.
+type MockHelloWorldLogicMockRecorder struct {
+	mock *MockHelloWorldLogic
+}
+
+// NewMockHelloWorldLogic creates a new mock instance.
+func NewMockHelloWorldLogic(ctrl *gomock.Controller) *MockHelloWorldLogic {
+	mock := &MockHelloWorldLogic{ctrl: ctrl}
+	mock.recorder = &MockHelloWorldLogicMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHelloWorldLogic) EXPECT() *MockHelloWorldLogicMockRecorder {
+	return m.recorder
+}
+
+// GetUserByName mocks base method.
+func (m *MockHelloWorldLogic) GetUserByName(ctx context.Context, name string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByName", ctx, name)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByName indicates an expected call of GetUserByName.
+func (mr *MockHelloWorldLogicMockRecorder) GetUserByName(ctx, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByName", reflect.TypeOf((*MockHelloWorldLogic)(nil).GetUserByName), ctx, name)
+}
+
+// Greet mocks base method.
+func (m *MockHelloWorldLogic) Greet(ctx context.Context, user *models.User, saveUser bool) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Greet", ctx, user, saveUser)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Greet indicates an expected call of Greet.
+func (mr *MockHelloWorldLogicMockRecorder) Greet(ctx, user, saveUser any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Greet", reflect.TypeOf((*MockHelloWorldLogic)(nil).Greet), ctx, user, saveUser)
+}
+
+// ListUsers mocks base method.
+func (m *MockHelloWorldLogic) ListUsers(ctx context.Context) ([]models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx)
+	ret0, _ := ret[0].([]models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockHelloWorldLogicMockRecorder) ListUsers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockHelloWorldLogic)(nil).ListUsers), ctx)
+}
@@ -0,0 +1,77 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// serve sends a request through a mux with the same probes as cmd/example
+func serve(t *testing.T, ping Pinger, target string) (int, string) {
+	t.Helper()
+
+	handler := NewHealthHandler(ping)
+	r := http.NewServeMux()
+	r.HandleFunc("GET /healthz", handler.Liveness())
+	r.HandleFunc("GET /readyz", handler.Readiness())
+	r.HandleFunc("GET /version", handler.Version())
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, target, nil))
+
+	return w.Code, w.Body.String()
+}
+
+func TestProbes(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		pingErr    error
+		wantStatus int
+		wantBody   string
+	}{
+		{
+			name:       "is alive even when the database is not",
+			target:     "/healthz",
+			pingErr:    errors.New("connection refused"),
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"ok"`,
+		},
+		{
+			name:       "is ready when the database answers",
+			target:     "/readyz",
+			wantStatus: http.StatusOK,
+			wantBody:   `"status":"ready"`,
+		},
+		{
+			name:       "is not ready when the database does not answer",
+			target:     "/readyz",
+			pingErr:    errors.New("connection refused"),
+			wantStatus: http.StatusServiceUnavailable,
+			wantBody:   `"status":"unavailable"`,
+		},
+		{
+			name:       "tells the version",
+			target:     "/version",
+			wantStatus: http.StatusOK,
+			wantBody:   `"version":`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ping := func(context.Context) error { return tt.pingErr }
+
+			status, body := serve(t, ping, tt.target)
+			if status != tt.wantStatus {
+				t.Errorf("expected status %d, got %d", tt.wantStatus, status)
+			}
+			if !strings.Contains(body, tt.wantBody) {
+				t.Errorf("expected body to contain %q, got %q", tt.wantBody, body)
+			}
+		})
+	}
+}
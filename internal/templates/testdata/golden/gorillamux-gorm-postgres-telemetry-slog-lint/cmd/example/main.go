@@ -0,0 +1,156 @@
+package main
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/plugin/opentelemetry/tracing"
+
+	"gorm.io/driver/postgres"
+
+	"github.com/gorilla/mux"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"net/http"
+
+	"example/internal/health"
+	"example/internal/helloworld/handlers"
+	"example/internal/helloworld/logic"
+	"example/internal/helloworld/repo"
+	"example/pkg/logging"
+	"example/pkg/middlewares"
+	"example/pkg/taskutils"
+	"example/pkg/telemetry"
+
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout is how long the requests in flight have to finish once a shutdown starts,
+// it is below the 30 seconds kubernetes waits before killing the pod
+const shutdownTimeout = 10 * time.Second
+
+// serviceName names the traces and metrics, OTEL_SERVICE_NAME replaces it
+const serviceName = "example"
+
+func main() {
+	var err error
+
+	// LOG_FORMAT is json, the default, or text, LOG_LEVEL is debug, info, the default, warn or error
+	logger, err := logging.New(os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.SetDefault(logger)
+	taskutils.SetLogger(logger)
+
+	// the traces and metrics are written to stdout, unless OTEL_EXPORTER_OTLP_ENDPOINT points to a collector
+	shutdownTelemetry, err := telemetry.Setup(context.Background(), serviceName)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "user=foo password=bar dbname=foobar host=localhost port=5432 sslmode=disable"
+	}
+	conn := postgres.Open(dsn)
+
+	db, err := gorm.Open(conn)
+	if err != nil {
+		log.Fatal(err)
+	}
+	// the queries are traced, and the connection pool is reported as metrics
+	err = db.Use(tracing.NewPlugin())
+	if err != nil {
+		log.Fatal(err)
+	}
+	// the readiness probe pings, and the shutdown closes, the connection pool gorm runs on
+	sqlDB, err := db.DB()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	helloWorldRepo := repo.NewGormRepo(db)
+
+	helloWorldLogic := logic.NewHelloWorldLogic(helloWorldRepo)
+	helloWorldHandler := handlers.NewHelloWorldHandler(helloWorldLogic)
+	healthHandler := health.NewHealthHandler(sqlDB.PingContext)
+
+	r := mux.NewRouter()
+	r.Use(middlewares.RequestLogger(logger), middlewares.RecoverPanic)
+	// the middlewares run once the route is matched, so the spans are named after its template
+	r.Use(otelhttp.NewMiddleware(serviceName, otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+		if tmpl, err := mux.CurrentRoute(req).GetPathTemplate(); err == nil {
+			return req.Method + " " + tmpl
+		}
+		return req.Method
+	})))
+
+	makeRoutes(r, helloWorldHandler, healthHandler)
+
+	srv := &http.Server{
+		Addr:              ":4000",
+		Handler:           r,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	// SIGINT (ctrl+c) and SIGTERM (docker stop, kubernetes) start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		// the server stopped on its own, e.g. the port is taken, there is nothing to shut down
+	case <-ctx.Done():
+		logger.Info("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = srv.Shutdown(shutdownCtx)
+		cancel()
+	}
+	// a second signal kills the process right away
+	stop()
+
+	// the tasks started with taskutils.BackgroundTask may still use the database
+	taskutils.WaitAll()
+	if closeErr := sqlDB.Close(); closeErr != nil {
+		logger.Error("closing the database failed", "error", closeErr)
+	}
+	// the spans and metrics that are left are exported before exiting
+	telemetryCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	if telemetryErr := shutdownTelemetry(telemetryCtx); telemetryErr != nil {
+		logger.Error("shutting down the telemetry failed", "error", telemetryErr)
+	}
+	cancel()
+
+	if err != nil {
+		logger.Error("the server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func makeRoutes(r *mux.Router, handler *handlers.GorillaMuxHelloWorldHandler, healthHandler *health.HttpHealthHandler) {
+	r.HandleFunc("/healthz", healthHandler.Liveness()).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", healthHandler.Readiness()).Methods(http.MethodGet)
+	r.HandleFunc("/version", healthHandler.Version()).Methods(http.MethodGet)
+	r.Handle("/metrics", telemetry.MetricsHandler()).Methods(http.MethodGet)
+
+	v1 := r.PathPrefix("/v1").Subrouter()
+	{
+		helloworld := v1.PathPrefix("/helloworld").Subrouter()
+		{
+			helloworld.HandleFunc("", handler.Greet()).Methods(http.MethodPost)
+			helloworld.HandleFunc("", handler.ListUsers()).Methods(http.MethodGet)
+			helloworld.HandleFunc("/{name}", handler.GetUserByName()).Methods(http.MethodGet)
+		}
+	}
+}
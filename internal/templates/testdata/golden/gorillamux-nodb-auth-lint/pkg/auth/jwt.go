@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minSecretLen is the size of a SHA-256 hash, RFC 7518 asks HS256 secrets to be at least as long
+const minSecretLen = 32
+
+// JWTConfig is the key tokens are verified with, Secret for HS256 or PublicKey for RS256,
+// and the claims they must have
+type JWTConfig struct {
+	// Secret verifies tokens signed with HS256
+	Secret []byte
+	// PublicKey verifies tokens signed with RS256, it's a PEM encoded RSA public key
+	PublicKey []byte
+	// Issuer, when set, must be the iss claim of the tokens
+	Issuer string
+	// Audience, when set, must be in the aud claim of the tokens
+	Audience string
+}
+
+// JWTConfigFromEnv reads JWT_SECRET, or JWT_PUBLIC_KEY, JWT_ISSUER and JWT_AUDIENCE
+func JWTConfigFromEnv() JWTConfig {
+	cfg := JWTConfig{
+		Issuer:   os.Getenv("JWT_ISSUER"),
+		Audience: os.Getenv("JWT_AUDIENCE"),
+	}
+	if secret := os.Getenv("JWT_SECRET"); secret != "" {
+		cfg.Secret = []byte(secret)
+	}
+	if key := os.Getenv("JWT_PUBLIC_KEY"); key != "" {
+		cfg.PublicKey = []byte(key)
+	}
+	return cfg
+}
+
+// JWTVerifier verifies the signature and the claims of the tokens signed with the key of its config
+type JWTVerifier struct {
+	key    any
+	parser *jwt.Parser
+}
+
+// NewJWTVerifier returns a JWTVerifier that only accepts the algorithm of the key in cfg, so a token can't choose
+// a weaker one, e.g. none, or HS256 with the public key as secret. Tokens must have an exp claim
+func NewJWTVerifier(cfg JWTConfig) (*JWTVerifier, error) {
+	var (
+		key    any
+		method jwt.SigningMethod
+	)
+	switch {
+	case len(cfg.Secret) > 0 && len(cfg.PublicKey) > 0:
+		return nil, errors.New("set either a secret or a public key to verify tokens, not both")
+	case len(cfg.Secret) > 0:
+		if len(cfg.Secret) < minSecretLen {
+			return nil, fmt.Errorf("the secret to verify tokens needs at least %d bytes", minSecretLen)
+		}
+		key, method = cfg.Secret, jwt.SigningMethodHS256
+	case len(cfg.PublicKey) > 0:
+		publicKey, err := jwt.ParseRSAPublicKeyFromPEM(cfg.PublicKey)
+		if err != nil {
+			return nil, err
+		}
+		key, method = publicKey, jwt.SigningMethodRS256
+	default:
+		return nil, errors.New("set a secret or a public key to verify tokens")
+	}
+
+	opts := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{method.Alg()}),
+		jwt.WithExpirationRequired(),
+	}
+	if cfg.Issuer != "" {
+		opts = append(opts, jwt.WithIssuer(cfg.Issuer))
+	}
+	if cfg.Audience != "" {
+		opts = append(opts, jwt.WithAudience(cfg.Audience))
+	}
+	return &JWTVerifier{key: key, parser: jwt.NewParser(opts...)}, nil
+}
+
+// Verify returns the Principal of token, or an error wrapping ErrInvalidCredentials
+func (v *JWTVerifier) Verify(token string) (Principal, error) {
+	claims := jwt.MapClaims{}
+	_, err := v.parser.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) {
+		return v.key, nil
+	})
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	subject, err := claims.GetSubject()
+	if err != nil {
+		return Principal{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+	return Principal{Subject: subject, Claims: claims}, nil
+}
+
+// BearerToken returns the token of an Authorization header, ErrNoCredentials when there is none
+func BearerToken(authorization string) (string, error) {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", ErrNoCredentials
+	}
+	return token, nil
+}
@@ -0,0 +1,209 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// newSecret generates the HS256 secret of a test
+func newSecret(t *testing.T) []byte {
+	t.Helper()
+
+	secret := make([]byte, minSecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		t.Fatal(err)
+	}
+	return secret
+}
+
+// newRSAKey generates the RS256 key pair of a test, along with its PEM encoded public key
+func newRSAKey(t *testing.T) (*rsa.PrivateKey, []byte) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+}
+
+func sign(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return token
+}
+
+func TestJWTVerifier(t *testing.T) {
+	secret := newSecret(t)
+	privateKey, publicKey := newRSAKey(t)
+	otherKey, _ := newRSAKey(t)
+
+	exp := time.Now().Add(time.Hour).Unix()
+	valid := jwt.MapClaims{"sub": "alice", "exp": exp}
+
+	tests := []struct {
+		name        string
+		cfg         JWTConfig
+		token       string
+		wantSubject string
+		wantErr     bool
+	}{
+		{
+			name:        "accepts HS256 tokens signed with the secret",
+			cfg:         JWTConfig{Secret: secret},
+			token:       sign(t, jwt.SigningMethodHS256, secret, valid),
+			wantSubject: "alice",
+		},
+		{
+			name:        "accepts RS256 tokens signed with the private key",
+			cfg:         JWTConfig{PublicKey: publicKey},
+			token:       sign(t, jwt.SigningMethodRS256, privateKey, valid),
+			wantSubject: "alice",
+		},
+		{
+			name:    "rejects tokens signed with another secret",
+			cfg:     JWTConfig{Secret: secret},
+			token:   sign(t, jwt.SigningMethodHS256, newSecret(t), valid),
+			wantErr: true,
+		},
+		{
+			name:    "rejects tokens signed with another private key",
+			cfg:     JWTConfig{PublicKey: publicKey},
+			token:   sign(t, jwt.SigningMethodRS256, otherKey, valid),
+			wantErr: true,
+		},
+		{
+			name:    "rejects HS256 tokens signed with the public key",
+			cfg:     JWTConfig{PublicKey: publicKey},
+			token:   sign(t, jwt.SigningMethodHS256, publicKey, valid),
+			wantErr: true,
+		},
+		{
+			name:    "rejects unsigned tokens",
+			cfg:     JWTConfig{Secret: secret},
+			token:   sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, valid),
+			wantErr: true,
+		},
+		{
+			name:    "rejects expired tokens",
+			cfg:     JWTConfig{Secret: secret},
+			token:   sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "alice", "exp": time.Now().Add(-time.Minute).Unix()}),
+			wantErr: true,
+		},
+		{
+			name:    "rejects tokens that never expire",
+			cfg:     JWTConfig{Secret: secret},
+			token:   sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "alice"}),
+			wantErr: true,
+		},
+		{
+			name:    "rejects tokens of another issuer",
+			cfg:     JWTConfig{Secret: secret, Issuer: "https://auth.example.com"},
+			token:   sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "alice", "exp": exp, "iss": "https://evil.example.com"}),
+			wantErr: true,
+		},
+		{
+			name:        "accepts tokens for its audience",
+			cfg:         JWTConfig{Secret: secret, Audience: "example"},
+			token:       sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "alice", "exp": exp, "aud": []string{"example"}}),
+			wantSubject: "alice",
+		},
+		{
+			name:    "rejects tokens for another audience",
+			cfg:     JWTConfig{Secret: secret, Audience: "example"},
+			token:   sign(t, jwt.SigningMethodHS256, secret, jwt.MapClaims{"sub": "alice", "exp": exp, "aud": "billing"}),
+			wantErr: true,
+		},
+		{
+			name:    "rejects what is not a token",
+			cfg:     JWTConfig{Secret: secret},
+			token:   "not.a.token",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verifier, err := NewJWTVerifier(tt.cfg)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			principal, err := verifier.Verify(tt.token)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidCredentials) {
+					t.Errorf("expected ErrInvalidCredentials, got %v", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if principal.Subject != tt.wantSubject {
+				t.Errorf("expected subject %q, got %q", tt.wantSubject, principal.Subject)
+			}
+		})
+	}
+}
+
+func TestNewJWTVerifierRejectsInvalidConfigs(t *testing.T) {
+	secret := newSecret(t)
+	_, publicKey := newRSAKey(t)
+
+	tests := []struct {
+		name string
+		cfg  JWTConfig
+	}{
+		{name: "without a key", cfg: JWTConfig{}},
+		{name: "with a secret and a public key", cfg: JWTConfig{Secret: secret, PublicKey: publicKey}},
+		{name: "with a short secret", cfg: JWTConfig{Secret: []byte("secret")}},
+		{name: "with a public key that is not PEM", cfg: JWTConfig{PublicKey: []byte("not a key")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewJWTVerifier(tt.cfg); err == nil {
+				t.Error("expected an error, got nil")
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		authorization string
+		want          string
+		wantErr       error
+	}{
+		{authorization: "Bearer abc", want: "abc"},
+		{authorization: "bearer abc", want: "abc"},
+		{authorization: "", wantErr: ErrNoCredentials},
+		{authorization: "Bearer", wantErr: ErrNoCredentials},
+		{authorization: "Basic YWxpY2U6c2VjcmV0", wantErr: ErrNoCredentials},
+	}
+
+	for _, tt := range tests {
+		got, err := BearerToken(tt.authorization)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%q: expected error %v, got %v", tt.authorization, tt.wantErr, err)
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %q, got %q", tt.authorization, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"database/sql"
+
+	_ "github.com/go-sql-driver/mysql"
+
+	"github.com/gorilla/mux"
+	"net/http"
+
+	"example/internal/health"
+	"example/internal/helloworld/handlers"
+	"example/internal/helloworld/logic"
+	"example/internal/helloworld/repo"
+	"example/pkg/logging"
+	"example/pkg/middlewares"
+	"example/pkg/taskutils"
+
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout is how long the requests in flight have to finish once a shutdown starts,
+// it is below the 30 seconds kubernetes waits before killing the pod
+const shutdownTimeout = 10 * time.Second
+
+func main() {
+	var err error
+
+	// LOG_FORMAT is json, the default, or text, LOG_LEVEL is debug, info, the default, warn or error
+	logger, err := logging.New(os.Getenv("LOG_FORMAT"), os.Getenv("LOG_LEVEL"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	logging.SetDefault(logger)
+	taskutils.SetLogger(logger)
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "username:password@/databasename?parseTime=true"
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	helloWorldRepo := repo.NewSqlRepo(db)
+
+	helloWorldLogic := logic.NewHelloWorldLogic(helloWorldRepo)
+	helloWorldHandler := handlers.NewHelloWorldHandler(helloWorldLogic)
+	healthHandler := health.NewHealthHandler(db.PingContext)
+
+	r := mux.NewRouter()
+	r.Use(middlewares.RequestLogger(logger), middlewares.RecoverPanic)
+
+	makeRoutes(r, helloWorldHandler, healthHandler)
+
+	srv := &http.Server{
+		Addr:              ":4000",
+		Handler:           r,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       15 * time.Second,
+		WriteTimeout:      15 * time.Second,
+		IdleTimeout:       60 * time.Second,
+	}
+
+	// SIGINT (ctrl+c) and SIGTERM (docker stop, kubernetes) start a graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		serverErr <- srv.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
+		// the server stopped on its own, e.g. the port is taken, there is nothing to shut down
+	case <-ctx.Done():
+		logger.Info("shutting down")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		err = srv.Shutdown(shutdownCtx)
+		cancel()
+	}
+	// a second signal kills the process right away
+	stop()
+
+	// the tasks started with taskutils.BackgroundTask may still use the database
+	taskutils.WaitAll()
+	if closeErr := db.Close(); closeErr != nil {
+		logger.Error("closing the database failed", "error", closeErr)
+	}
+
+	if err != nil {
+		logger.Error("the server failed", "error", err)
+		os.Exit(1)
+	}
+}
+
+func makeRoutes(r *mux.Router, handler *handlers.GorillaMuxHelloWorldHandler, healthHandler *health.HttpHealthHandler) {
+	r.HandleFunc("/healthz", healthHandler.Liveness()).Methods(http.MethodGet)
+	r.HandleFunc("/readyz", healthHandler.Readiness()).Methods(http.MethodGet)
+	r.HandleFunc("/version", healthHandler.Version()).Methods(http.MethodGet)
+
+	v1 := r.PathPrefix("/v1").Subrouter()
+	{
+		helloworld := v1.PathPrefix("/helloworld").Subrouter()
+		{
+			helloworld.HandleFunc("", handler.Greet()).Methods(http.MethodPost)
+			helloworld.HandleFunc("", handler.ListUsers()).Methods(http.MethodGet)
+			helloworld.HandleFunc("/{name}", handler.GetUserByName()).Methods(http.MethodGet)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"database/sql"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+func main() {
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "username:password@/databasename?parseTime=true"
+	}
+
+	db, err := sql.Open("mysql", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	query := `CREATE TABLE example.users (
+					id BIGINT UNSIGNED auto_increment NOT NULL PRIMARY KEY,
+					name varchar(100) NOT NULL UNIQUE,
+					registered_at DATETIME DEFAULT NOW() NOT NULL
+				);`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+}
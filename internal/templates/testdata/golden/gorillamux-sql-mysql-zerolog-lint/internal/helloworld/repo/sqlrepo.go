@@ -0,0 +1,99 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"example/internal/helloworld/repositoryerrors"
+	"example/internal/models"
+)
+
+// dbtx is satisfied by both *sql.DB and *sql.Tx, so the repository can also run inside a transaction
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type sqlRepo struct {
+	db dbtx
+}
+
+func NewSqlRepo(db *sql.DB) *sqlRepo {
+	return &sqlRepo{
+		db: db,
+	}
+}
+
+func (r *sqlRepo) SaveGreetedUser(ctx context.Context, user *models.User) error {
+
+	query := `INSERT INTO users (name)
+				VALUES(?)
+				 ON DUPLICATE KEY UPDATE id=id`
+
+	_, err := r.db.ExecContext(ctx, query, user.Name)
+	if err != nil {
+		return err
+	}
+
+	// not every DBMS supports RETURNING, so the saved user is read back instead
+	saved, err := r.GetUser(ctx, user.Name)
+	if err != nil {
+		return err
+	}
+	*user = saved
+
+	return nil
+}
+
+func (r *sqlRepo) GetUser(ctx context.Context, name string) (models.User, error) {
+	var user models.User
+
+	query := `SELECT name, id, registered_at FROM users
+				WHERE name = ?`
+
+	values := []any{&user.Name, &user.ID, &user.RegisteredAt}
+
+	err := r.db.QueryRowContext(ctx, query, name).Scan(values...)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return models.User{}, repositoryerrors.ErrRecordNotFound
+		default:
+			return models.User{}, err
+		}
+	}
+
+	return user, nil
+}
+
+func (r *sqlRepo) GetAllGreetedUsers(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	query := `SELECT name, id, registered_at
+				FROM users`
+
+	result, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return []models.User{}, nil
+		default:
+			return []models.User{}, err
+		}
+	}
+	defer result.Close()
+
+	for result.Next() {
+		var user models.User
+		err := result.Scan(&user.Name, &user.ID, &user.RegisteredAt)
+		if err != nil {
+			return []models.User{}, err
+		}
+		users = append(users, user)
+	}
+	if err := result.Err(); err != nil {
+		return []models.User{}, err
+	}
+
+	return users, nil
+}
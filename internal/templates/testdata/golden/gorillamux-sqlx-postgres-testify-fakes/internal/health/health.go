@@ -0,0 +1,63 @@
+// Package health answers the probes of whatever runs the server, and tells which build is running
+package health
+
+import (
+	"context"
+	"runtime/debug"
+	"time"
+)
+
+// Version is the version of the server, set it when building with
+// go build -ldflags "-X example/internal/health.Version=v1.2.3" ./cmd/example
+var Version = "dev"
+
+// readyTimeout is how long the dependencies of the server have to answer a readiness probe
+const readyTimeout = 2 * time.Second
+
+// Pinger tells whether a dependency of the server, such as the database, can be reached
+type Pinger func(ctx context.Context) error
+
+// BuildInfo is what /version answers
+type BuildInfo struct {
+	Version   string `json:"version"`
+	GoVersion string `json:"go_version,omitempty"`
+	Revision  string `json:"revision,omitempty"`
+	Time      string `json:"time,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+}
+
+// ReadBuildInfo returns Version, along with the go version and the commit the go command stamped into the binary
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{Version: Version}
+	build, ok := debug.ReadBuildInfo()
+	if !ok {
+		return info
+	}
+	info.GoVersion = build.GoVersion
+	if info.Version == "dev" && build.Main.Version != "" && build.Main.Version != "(devel)" {
+		info.Version = build.Main.Version
+	}
+	for _, setting := range build.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			info.Revision = setting.Value
+		case "vcs.time":
+			info.Time = setting.Value
+		case "vcs.modified":
+			info.Modified = setting.Value == "true"
+		}
+	}
+	return info
+}
+
+// ready pings every dependency, the server is not ready while one of them can't be reached
+func ready(ctx context.Context, pingers []Pinger) error {
+	ctx, cancel := context.WithTimeout(ctx, readyTimeout)
+	defer cancel()
+	for _, ping := range pingers {
+		if err := ping(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
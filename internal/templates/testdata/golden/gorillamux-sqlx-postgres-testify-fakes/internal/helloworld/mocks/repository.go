@@ -0,0 +1,36 @@
+package mocks
+
+import (
+	"context"
+
+	"example/internal/models"
+)
+
+// HelloWorldRepository is a hand-written fake of logic.HelloWorldRepository, set the functions your test needs.
+// Calling a method whose function is not set panics
+type HelloWorldRepository struct {
+	SaveGreetedUserFunc    func(ctx context.Context, user *models.User) error
+	GetUserFunc            func(ctx context.Context, name string) (models.User, error)
+	GetAllGreetedUsersFunc func(ctx context.Context) ([]models.User, error)
+}
+
+func (f *HelloWorldRepository) SaveGreetedUser(ctx context.Context, user *models.User) error {
+	if f.SaveGreetedUserFunc == nil {
+		panic("mocks: unexpected call to HelloWorldRepository.SaveGreetedUser")
+	}
+	return f.SaveGreetedUserFunc(ctx, user)
+}
+
+func (f *HelloWorldRepository) GetUser(ctx context.Context, name string) (models.User, error) {
+	if f.GetUserFunc == nil {
+		panic("mocks: unexpected call to HelloWorldRepository.GetUser")
+	}
+	return f.GetUserFunc(ctx, name)
+}
+
+func (f *HelloWorldRepository) GetAllGreetedUsers(ctx context.Context) ([]models.User, error) {
+	if f.GetAllGreetedUsersFunc == nil {
+		panic("mocks: unexpected call to HelloWorldRepository.GetAllGreetedUsers")
+	}
+	return f.GetAllGreetedUsersFunc(ctx)
+}
@@ -0,0 +1,139 @@
+package repo
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/jmoiron/sqlx"
+
+	_ "github.com/lib/pq"
+
+	_ "modernc.org/sqlite"
+
+	"github.com/stretchr/testify/require"
+
+	"example/internal/helloworld/repositoryerrors"
+	"example/internal/models"
+)
+
+// newTestRepo connects to DB_TEST_DSN when it is set (for example, the database started by your compose file),
+// otherwise it falls back to an in-memory SQLite database if the dialect permits it.
+// The repository runs in a transaction that is rolled back when the test ends, so the database is left untouched
+func newTestRepo(t *testing.T) *sqlxRepo {
+	t.Helper()
+
+	driver, dsn := "postgres", os.Getenv("DB_TEST_DSN")
+	schema := `CREATE TABLE IF NOT EXISTS users (
+					id BIGSERIAL NOT NULL PRIMARY KEY,
+					name TEXT NOT NULL UNIQUE,
+					registered_at timestamp NOT NULL DEFAULT NOW()
+				)`
+	if dsn == "" {
+
+		// the queries used by the repo are valid SQLite too, so an in-memory database is enough
+		driver, dsn = "sqlite", ":memory:"
+		schema = `CREATE TABLE users (
+					id INTEGER PRIMARY KEY AUTOINCREMENT,
+					name TEXT NOT NULL UNIQUE,
+					registered_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+				)`
+
+	}
+
+	db, err := sqlx.Open(driver, dsn)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// every connection to an in-memory SQLite database gets its own database
+	db.SetMaxOpenConns(1)
+	t.Cleanup(func() { db.Close() })
+
+	if _, err := db.Exec(schema); err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := db.Beginx()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := tx.Rollback(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	return &sqlxRepo{db: tx}
+}
+
+func TestSaveGreetedUser(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+
+	user := models.User{Name: "gopher"}
+	err := r.SaveGreetedUser(ctx, &user)
+	require.NoError(t, err)
+	require.NotZero(t, user.ID)
+
+	// greeting the same user twice must not create a new record
+	again := models.User{Name: "gopher"}
+	err = r.SaveGreetedUser(ctx, &again)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, again.ID)
+}
+
+func TestGetUser(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+
+	saved := models.User{Name: "gopher"}
+	if err := r.SaveGreetedUser(ctx, &saved); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		lookup  string
+		wantErr error
+	}{
+		{name: "existing user", lookup: "gopher"},
+		{name: "missing user", lookup: "nobody", wantErr: repositoryerrors.ErrRecordNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user, err := r.GetUser(ctx, tt.lookup)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Equal(t, saved.ID, user.ID)
+			require.Equal(t, saved.Name, user.Name)
+		})
+	}
+}
+
+func TestGetAllGreetedUsers(t *testing.T) {
+	ctx := context.Background()
+	r := newTestRepo(t)
+
+	// the database behind DB_TEST_DSN may already hold users, so only the ones saved here are looked for
+	names := []string{"alice", "bob"}
+	for _, name := range names {
+		if err := r.SaveGreetedUser(ctx, &models.User{Name: name}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	users, err := r.GetAllGreetedUsers(ctx)
+	require.NoError(t, err)
+
+	found := make(map[string]bool, len(users))
+	for _, user := range users {
+		found[user.Name] = true
+	}
+	for _, name := range names {
+		require.True(t, found[name], "expected %s among the greeted users", name)
+	}
+}
@@ -0,0 +1,20 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+
+	"example/pkg/httphelpers"
+)
+
+func RecoverPanic(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				w.Header().Set("Connection", "close")
+				httphelpers.StatusInternalServerErrorResponse(w, r, fmt.Errorf("%s", err))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
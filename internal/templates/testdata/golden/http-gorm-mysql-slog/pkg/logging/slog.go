@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// New returns a Logger that writes to stderr, JSON entries when format is json or empty,
+// or text ones when it's text. level is debug, info, warn or error, info when empty
+func New(format, level string) (Logger, error) {
+	var lvl slog.Level
+	if level != "" {
+		if err := lvl.UnmarshalText([]byte(level)); err != nil {
+			return nil, fmt.Errorf("unknown log level %q, use debug, info, warn or error", level)
+		}
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+	var handler slog.Handler
+	switch format {
+	case "", "json":
+		handler = slog.NewJSONHandler(os.Stderr, opts)
+	case "text":
+		handler = slog.NewTextHandler(os.Stderr, opts)
+	default:
+		return nil, fmt.Errorf("unknown log format %q, use json or text", format)
+	}
+
+	return slogLogger{l: slog.New(handler)}, nil
+}
+
+func newDefault() Logger {
+	return slogLogger{l: slog.New(slog.NewJSONHandler(os.Stderr, nil))}
+}
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+func (s slogLogger) Debug(msg string, args ...any) {
+	s.l.Debug(msg, args...)
+}
+
+func (s slogLogger) Info(msg string, args ...any) {
+	s.l.Info(msg, args...)
+}
+
+func (s slogLogger) Warn(msg string, args ...any) {
+	s.l.Warn(msg, args...)
+}
+
+func (s slogLogger) Error(msg string, args ...any) {
+	s.l.Error(msg, args...)
+}
+
+func (s slogLogger) With(args ...any) Logger {
+	return slogLogger{l: s.l.With(args...)}
+}
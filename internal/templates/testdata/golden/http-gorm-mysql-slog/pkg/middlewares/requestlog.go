@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"net/http"
+	"time"
+
+	"example/pkg/logging"
+)
+
+// RequestLogger logs every request once it's answered. Its ID is the one of the X-Request-ID header, or a new one,
+// it's answered in the same header and added to the entries of the logger of the request context
+func RequestLogger(logger logging.Logger) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			id := logging.RequestIDOrNew(r.Header.Get(logging.RequestIDHeader))
+			w.Header().Set(logging.RequestIDHeader, id)
+
+			reqLogger := logger.With("request_id", id)
+			ctx := logging.WithRequestID(r.Context(), id)
+			r = r.WithContext(logging.NewContext(ctx, reqLogger))
+
+			sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+			next.ServeHTTP(sw, r)
+
+			args := []any{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", sw.status,
+				"duration", time.Since(start),
+			}
+			if sw.status >= http.StatusInternalServerError {
+				reqLogger.Error("request", args...)
+				return
+			}
+			reqLogger.Info("request", args...)
+		})
+	}
+}
+
+// statusWriter remembers the status the handler answered with
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+// Unwrap lets http.ResponseController reach the ResponseWriter of the server
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"example/internal/helloworld/logicerrors"
+	"example/internal/models"
+	"example/pkg/httphelpers"
+)
+
+type HttpHelloWorldHandler struct {
+	logic HelloWorldLogic
+}
+
+type HelloWorldLogic interface {
+	Greet(ctx context.Context, user *models.User, saveUser bool) (string, error)
+	GetUserByName(ctx context.Context, name string) (models.User, error)
+	ListUsers(ctx context.Context) ([]models.User, error)
+}
+
+func NewHelloWorldHandler(logic HelloWorldLogic) *HttpHelloWorldHandler {
+	return &HttpHelloWorldHandler{
+		logic: logic,
+	}
+}
+
+// post /helloworld
+func (h *HttpHelloWorldHandler) Greet() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var userInput struct {
+			Name string `json:"name"`
+		}
+		if err := httphelpers.JSONDecode(w, r, &userInput); err != nil {
+			httphelpers.StatusBadRequestResponse(w, err.Error())
+			return
+		}
+
+		save := r.URL.Query().Get("save")
+		if save == "" {
+			save = "false"
+		}
+
+		user := models.User{Name: userInput.Name}
+		saveUser, err := strconv.ParseBool(save)
+		if err != nil {
+			httphelpers.StatusBadRequestResponse(w, "invalid save query parameter")
+			return
+		}
+
+		helloStr, err := h.logic.Greet(r.Context(), &user, saveUser)
+		if err != nil {
+			httphelpers.StatusInternalServerErrorResponse(w, r, err)
+			return
+		}
+
+		httphelpers.StatusOKJSONPayloadResponse(w, map[string]any{"message": helloStr})
+	}
+}
+
+// get /helloworld/:name
+func (h *HttpHelloWorldHandler) GetUserByName() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+
+		user, err := h.logic.GetUserByName(r.Context(), name)
+		if err != nil {
+			switch {
+			case errors.Is(err, logicerrors.ErrUserDoesNotExist):
+				httphelpers.StatusBadRequestResponse(w, "user not found")
+			default:
+				httphelpers.StatusInternalServerErrorResponse(w, r, err)
+			}
+			return
+		}
+
+		httphelpers.StatusOKJSONPayloadResponse(w, user)
+	}
+}
+
+// get /helloworld
+func (h *HttpHelloWorldHandler) ListUsers() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		users, err := h.logic.ListUsers(r.Context())
+		if err != nil {
+			httphelpers.StatusInternalServerErrorResponse(w, r, err)
+			return
+		}
+
+		httphelpers.StatusOKJSONPayloadResponse(w, users)
+	}
+}
@@ -0,0 +1,44 @@
+// Package auth authenticates requests with JWTs or API keys, the middlewares in pkg/middlewares add the Principal
+// of a request to its context, where handlers read it with FromContext
+package auth
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+var (
+	ErrNoCredentials      = errors.New("no credentials")
+	ErrInvalidCredentials = errors.New("invalid credentials")
+)
+
+// Principal is who a request is made by
+type Principal struct {
+	// Subject is the sub claim of the token, or the name of the API key
+	Subject string
+	// Claims are the claims of the token, nil for API keys
+	Claims jwt.MapClaims
+}
+
+// HasScope reports whether the scope claim, a space separated list as in OAuth 2.0, contains scope
+func (p Principal) HasScope(scope string) bool {
+	scopes, _ := p.Claims["scope"].(string)
+	return slices.Contains(strings.Fields(scopes), scope)
+}
+
+type contextKey struct{}
+
+// NewContext returns a copy of ctx that carries p
+func NewContext(ctx context.Context, p Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, p)
+}
+
+// FromContext returns the Principal ctx carries, false when the request was not authenticated
+func FromContext(ctx context.Context) (Principal, bool) {
+	p, ok := ctx.Value(contextKey{}).(Principal)
+	return p, ok
+}
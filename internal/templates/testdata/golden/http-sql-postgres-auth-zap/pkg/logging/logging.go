@@ -0,0 +1,86 @@
+// Package logging is what the project logs with, only New knows the library behind Logger
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+)
+
+// RequestIDHeader is the header request IDs are read from and answered with
+const RequestIDHeader = "X-Request-ID"
+
+// Logger writes structured entries, args alternate keys and values, e.g. logger.Info("user saved", "name", name)
+type Logger interface {
+	Debug(msg string, args ...any)
+	Info(msg string, args ...any)
+	Warn(msg string, args ...any)
+	Error(msg string, args ...any)
+	// With returns a Logger that adds args to every entry
+	With(args ...any) Logger
+}
+
+var defaultLogger = newDefault()
+
+// Default returns the Logger set with SetDefault, or one that writes JSON entries from info on
+func Default() Logger {
+	return defaultLogger
+}
+
+// SetDefault makes l the Logger of the code that has no other at hand,
+// call it before starting the goroutines that log
+func SetDefault(l Logger) {
+	defaultLogger = l
+}
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestIDKey
+)
+
+// NewContext returns a copy of ctx that carries l
+func NewContext(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// FromContext returns the Logger ctx carries, e.g. the one of a request, which adds its ID, or Default
+func FromContext(ctx context.Context) Logger {
+	if l, ok := ctx.Value(loggerKey).(Logger); ok {
+		return l
+	}
+	return Default()
+}
+
+// WithRequestID returns a copy of ctx that carries the ID of the request
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey, id)
+}
+
+// RequestID returns the ID of the request ctx belongs to, empty outside of a request
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string)
+	return id
+}
+
+// RequestIDOrNew returns id when it's a sensible request ID, such as the one a proxy sent in RequestIDHeader,
+// otherwise a new random one
+func RequestIDOrNew(id string) string {
+	if id != "" && len(id) <= 64 && isPrintableASCII(id) {
+		return id
+	}
+	b := make([]byte, 16)
+	// crypto/rand only fails when the OS can't provide randomness, a zero ID is still a usable one
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func isPrintableASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] <= ' ' || s[i] > '~' {
+			return false
+		}
+	}
+	return true
+}
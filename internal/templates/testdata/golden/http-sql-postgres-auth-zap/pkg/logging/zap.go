@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// New returns a Logger that writes to stderr, JSON entries when format is json or empty,
+// or text ones when it's text. level is debug, info, warn or error, info when empty
+func New(format, level string) (Logger, error) {
+	cfg := zap.NewProductionConfig()
+	if level != "" {
+		lvl, err := zapcore.ParseLevel(level)
+		if err != nil {
+			return nil, fmt.Errorf("unknown log level %q, use debug, info, warn or error", level)
+		}
+		cfg.Level = zap.NewAtomicLevelAt(lvl)
+	}
+
+	switch format {
+	case "", "json":
+	case "text":
+		cfg.Encoding = "console"
+	default:
+		return nil, fmt.Errorf("unknown log format %q, use json or text", format)
+	}
+
+	l, err := cfg.Build()
+	if err != nil {
+		return nil, err
+	}
+	return zapLogger{s: l.Sugar()}, nil
+}
+
+func newDefault() Logger {
+	return zapLogger{s: zap.Must(zap.NewProduction()).Sugar()}
+}
+
+type zapLogger struct {
+	s *zap.SugaredLogger
+}
+
+func (z zapLogger) Debug(msg string, args ...any) {
+	z.s.Debugw(msg, args...)
+}
+
+func (z zapLogger) Info(msg string, args ...any) {
+	z.s.Infow(msg, args...)
+}
+
+func (z zapLogger) Warn(msg string, args ...any) {
+	z.s.Warnw(msg, args...)
+}
+
+func (z zapLogger) Error(msg string, args ...any) {
+	z.s.Errorw(msg, args...)
+}
+
+func (z zapLogger) With(args ...any) Logger {
+	return zapLogger{s: z.s.With(args...)}
+}
@@ -0,0 +1,64 @@
+package middlewares
+
+import (
+	"net/http"
+
+	"example/pkg/auth"
+	"example/pkg/httphelpers"
+)
+
+// RequireJWT answers with a 401 unless the Authorization header has a bearer token verifier accepts,
+// the Principal of the token is added to the request context
+func RequireJWT(verifier *auth.JWTVerifier) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := auth.BearerToken(r.Header.Get("Authorization"))
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				httphelpers.StatusUnauthorizedResponse(w)
+				return
+			}
+			principal, err := verifier.Verify(token)
+			if err != nil {
+				w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+				httphelpers.StatusUnauthorizedResponse(w)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireAPIKey answers with a 401 unless the X-API-Key header has one of keys,
+// the Principal named after the key is added to the request context
+func RequireAPIKey(keys *auth.APIKeys) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, err := keys.Verify(r.Header.Get(auth.APIKeyHeader))
+			if err != nil {
+				httphelpers.StatusUnauthorizedResponse(w)
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(auth.NewContext(r.Context(), principal)))
+		})
+	}
+}
+
+// RequireScope answers with a 403 unless the Principal RequireJWT added has scope,
+// and with a 401 when there is no Principal
+func RequireScope(scope string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			principal, ok := auth.FromContext(r.Context())
+			if !ok {
+				httphelpers.StatusUnauthorizedResponse(w)
+				return
+			}
+			if !principal.HasScope(scope) {
+				httphelpers.StatusForbiddenResponse(w)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
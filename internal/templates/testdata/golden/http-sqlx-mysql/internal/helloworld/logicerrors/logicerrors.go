@@ -0,0 +1,7 @@
+package logicerrors
+
+import "errors"
+
+var (
+	ErrUserDoesNotExist = errors.New("user does not exists")
+)
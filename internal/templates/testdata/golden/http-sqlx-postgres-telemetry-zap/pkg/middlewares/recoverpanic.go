@@ -0,0 +1,23 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"example/pkg/httphelpers"
+	"example/pkg/logging"
+)
+
+func RecoverPanic(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer func() {
+			if err := recover(); err != nil {
+				logging.FromContext(r.Context()).Error("panic recovered", "panic", err, "stack", string(debug.Stack()))
+				w.Header().Set("Connection", "close")
+				httphelpers.StatusInternalServerErrorResponse(w, r, fmt.Errorf("%s", err))
+			}
+		}()
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,48 @@
+package taskutils
+
+import (
+	"runtime/debug"
+	"sync"
+
+	"example/pkg/logging"
+)
+
+var (
+	logger logging.Logger
+	wg     = sync.WaitGroup{}
+)
+
+// Set the logger to use on panic recovery
+//
+// By default, it uses logging.Default() to log the panic, along with its stack trace
+func SetLogger(l logging.Logger) {
+	logger = l
+}
+
+// Setup Background task with with recover, and assures graceful exit on program exit
+// when calling WaitAll()
+//
+// WARNING: This function by itself does not use a separate goroutine. Use `go` to run it concurrently
+//
+// Technically a little bit slower than using a goroutine, if you know it will not panic and don't care about
+// a graceful exit, you should use a goroutine.
+func BackgroundTask(task func()) {
+	wg.Add(1)
+	defer func() {
+		defer wg.Done()
+		if err := recover(); err != nil {
+			l := logger
+			if l == nil {
+				l = logging.Default()
+			}
+			l.Error("background task panicked", "panic", err, "stack", string(debug.Stack()))
+		}
+	}()
+
+	task()
+}
+
+// Block until all background tasks are finished
+func WaitAll() {
+	wg.Wait()
+}
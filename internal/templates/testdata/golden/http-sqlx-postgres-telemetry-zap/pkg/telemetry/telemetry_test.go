@@ -0,0 +1,73 @@
+package telemetry
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+)
+
+func TestExporter(t *testing.T) {
+	tests := []struct {
+		name     string
+		exporter string
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{name: "writes to stdout without a collector", want: exporterConsole},
+		{name: "exports to the collector of the endpoint", endpoint: "http://localhost:4318", want: exporterOtlp},
+		{name: "can be turned off", exporter: "none", endpoint: "http://localhost:4318", want: exporterNone},
+		{name: "rejects unknown exporters", exporter: "jaeger", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("OTEL_EXPORTER_OTLP_ENDPOINT", tt.endpoint)
+
+			got, err := exporter(tt.exporter, "OTEL_EXPORTER_OTLP_TRACES_ENDPOINT")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("expected error: %v, got %v", tt.wantErr, err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %q, got %q", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMetricsHandlerServesMeters(t *testing.T) {
+	// nothing is exported, so the test needs no collector and writes nothing to stdout
+	t.Setenv("OTEL_TRACES_EXPORTER", exporterNone)
+	t.Setenv("OTEL_METRICS_EXPORTER", exporterNone)
+
+	ctx := context.Background()
+	shutdown, err := Setup(ctx, "test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		if err := shutdown(ctx); err != nil {
+			t.Error(err)
+		}
+	}()
+
+	counter, err := otel.Meter("test").Int64Counter("greetings")
+	if err != nil {
+		t.Fatal(err)
+	}
+	counter.Add(ctx, 1)
+
+	w := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if w.Code != http.StatusOK {
+		t.Errorf("expected status %d, got %d", http.StatusOK, w.Code)
+	}
+	if body := w.Body.String(); !strings.Contains(body, "greetings_total") {
+		t.Errorf("expected the metrics to contain greetings_total, got:\n%s", body)
+	}
+}
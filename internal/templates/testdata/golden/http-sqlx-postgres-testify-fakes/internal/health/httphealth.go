@@ -0,0 +1,42 @@
+package health
+
+import (
+	"net/http"
+
+	"example/pkg/httphelpers"
+)
+
+type HttpHealthHandler struct {
+	pingers []Pinger
+}
+
+func NewHealthHandler(pingers ...Pinger) *HttpHealthHandler {
+	return &HttpHealthHandler{
+		pingers: pingers,
+	}
+}
+
+// get /healthz, the server is alive as long as it answers
+func (h *HttpHealthHandler) Liveness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httphelpers.StatusOKJSONPayloadResponse(w, map[string]string{"status": "ok"})
+	}
+}
+
+// get /readyz, the server is ready to take traffic once its dependencies answer
+func (h *HttpHealthHandler) Readiness() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := ready(r.Context(), h.pingers); err != nil {
+			httphelpers.CustomStatusJSONPayloadResponse(w, http.StatusServiceUnavailable, map[string]string{"status": "unavailable"})
+			return
+		}
+		httphelpers.StatusOKJSONPayloadResponse(w, map[string]string{"status": "ready"})
+	}
+}
+
+// get /version
+func (h *HttpHealthHandler) Version() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		httphelpers.StatusOKJSONPayloadResponse(w, ReadBuildInfo())
+	}
+}
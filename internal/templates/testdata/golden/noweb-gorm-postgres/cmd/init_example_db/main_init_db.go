@@ -0,0 +1,31 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"example/internal/models"
+	"gorm.io/gorm"
+
+	"gorm.io/driver/postgres"
+)
+
+func main() {
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "user=foo password=bar dbname=foobar host=localhost port=5432 sslmode=disable"
+	}
+	conn := postgres.Open(dsn)
+
+	db, err := gorm.Open(conn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err := db.AutoMigrate(&models.User{}); err != nil {
+		log.Fatal(err)
+	}
+
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"database/sql"
+
+	_ "github.com/lib/pq"
+)
+
+func main() {
+
+	// DB_DSN, e.g. set from the secret of the deployment, replaces the DSN of the local database
+	dsn := os.Getenv("DB_DSN")
+	if dsn == "" {
+		dsn = "user=foo password=bar dbname=foobar host=localhost port=5432 sslmode=disable"
+	}
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	query := `CREATE TABLE users (
+					id BIGSERIAL NOT NULL PRIMARY KEY,
+					name TEXT NOT NULL UNIQUE,
+					registered_at timestamp NOT NULL DEFAULT NOW()
+				);`
+
+	_, err = db.Exec(query)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+}
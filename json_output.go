@@ -0,0 +1,26 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+
+	"github.com/fedevilensky/go-scaffold/scaffold"
+)
+
+// generateJSON generates the project without the terminal UI, writing every event as a line of JSON
+// to stdout, so editors and CI pipelines can follow the progress
+func generateJSON(cfg scaffold.Config, opts scaffold.Options) error {
+	enc := json.NewEncoder(os.Stdout)
+	done := false
+	opts.Progress = func(e scaffold.Event) {
+		done = done || e.Kind == scaffold.EventDone
+		enc.Encode(e)
+	}
+	err := scaffold.Generate(context.Background(), cfg, opts)
+	if err != nil && !done {
+		// Generate failed before it started generating
+		enc.Encode(scaffold.Event{Kind: scaffold.EventDone, Err: err})
+	}
+	return err
+}
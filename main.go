@@ -1,21 +1,46 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/fedevilensky/go-scaffold/internal/catalog"
 	"github.com/fedevilensky/go-scaffold/internal/project"
-	"github.com/fedevilensky/go-scaffold/internal/templates"
+	"github.com/fedevilensky/go-scaffold/scaffold"
 )
 
 func main() {
 	var strpath string
+	root := "."
 
-	args := os.Args[1:]
+	specPath := flag.String("spec", "", "JSON file with the project configuration, dependencies can be pinned to a version")
+	preset := flag.String("preset", "", "start from a preset: "+strings.Join(scaffold.Presets(), ", "))
+	offline := flag.Bool("offline", false, "use only the modules in the local module cache, see go-scaffold warm")
+	skipMissing := flag.Bool("skip-missing", false, "with --offline, leave out the modules missing from the module cache instead of failing")
+	gitRepo := flag.String("git", project.GitNone, "none, init to create a git repository with a .gitignore and a .gitattributes, or commit to commit the project to it too")
+	tasksFile := flag.String("tasks", project.TasksNone, "none, make to generate a Makefile or task to generate a Taskfile.yml, with build, test, lint and more")
+	lintConfig := flag.Bool("lint", false, "generate a .golangci.yml, an .editorconfig and a .pre-commit-config.yaml")
+	ciPipeline := flag.String("ci", project.CINone, "none, github to generate a GitHub Actions workflow or gitlab to generate a .gitlab-ci.yml")
+	deployManifests := flag.String("deploy", project.DeployNone, "none, kustomize to generate a kustomize base or helm to generate a Helm chart")
+	loggingLibrary := flag.String("logging", project.LoggingStd, "log to keep the standard log package, or slog, zap or zerolog to log structured entries with request IDs")
+	telemetryInstrumentation := flag.Bool("telemetry", false, "instrument the server and the database with OpenTelemetry, and serve Prometheus metrics on /metrics")
+	authMiddlewares := flag.Bool("auth", false, "generate pkg/auth and middlewares that authenticate requests with JWTs or API keys")
+	output := flag.String("output", "text", "text shows the terminal UI, json writes the progress as newline delimited JSON events to stdout, it needs --spec or --preset")
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) > 0 && args[0] == "warm" {
+		warm(args[1:])
+		return
+	}
 	pwd, err := os.Getwd()
 	if err != nil {
 		log.Fatal(err)
@@ -25,30 +50,212 @@ func main() {
 		if strpath == "help" || strpath == "h" {
 			fmt.Println("go-scaffold:                will create a new project in $PWD")
 			fmt.Println("go-scaffold <project-name>: will create a new project in $PWD/<project-name>")
+			fmt.Println("go-scaffold --spec <file> [project-name]: will start from the configuration in <file>")
+			fmt.Println("go-scaffold --preset <preset> [project-name]: will start from a preset configuration, one of:",
+				strings.Join(scaffold.Presets(), ", "))
+			fmt.Println("go-scaffold --offline [--skip-missing] ...: will only use the modules in the local module cache")
+			fmt.Println("go-scaffold --git init|commit ...: will create a git repository for the project, and commit it")
+			fmt.Println("go-scaffold --tasks make|task ...: will generate a Makefile or a Taskfile.yml with the usual tasks")
+			fmt.Println("go-scaffold --lint ...: will generate the configuration of golangci-lint, editorconfig and pre-commit")
+			fmt.Println("go-scaffold --ci github|gitlab ...: will generate a CI pipeline for GitHub Actions or GitLab CI")
+			fmt.Println("go-scaffold --deploy kustomize|helm ...: will generate Kubernetes manifests or a Helm chart")
+			fmt.Println("go-scaffold --logging slog|zap|zerolog ...: will log structured entries, with a request logger and request IDs")
+			fmt.Println("go-scaffold --telemetry ...: will trace and measure the server and the database with OpenTelemetry")
+			fmt.Println("go-scaffold --auth ...: will generate JWT and API key middlewares, and tests for them")
+			fmt.Println("go-scaffold --output json --spec <file> | --preset <preset> [project-name]: will generate the project without questions,",
+				"writing its progress as JSON events to stdout")
+			fmt.Println("go-scaffold warm <preset> | warm --spec <file>: will download what the configuration needs to the module cache")
 			return
 		}
 		if err := os.MkdirAll(strpath, 0755); err != nil {
 			log.Fatal(err)
 		}
-		if err := os.Chdir(strpath); err != nil {
-			log.Fatal(err)
-		}
+		root = strpath
 	} else {
 		strpath = filepath.Base(pwd)
 	}
 
-	if _, err := os.Stat(strpath); err == nil {
+	if _, err := os.Stat(filepath.Join(root, "go.mod")); err == nil {
 		log.Fatal("go.mod already exists")
 	}
-	if _, err := os.Stat("go.sum"); err == nil {
+	if _, err := os.Stat(filepath.Join(root, "go.sum")); err == nil {
 		log.Fatal("go.sum already exists")
 	}
 
+	if *output != "text" && *output != "json" {
+		log.Fatalf("unknown output %q, use text or json", *output)
+	}
+	if *gitRepo != project.GitNone && *gitRepo != project.GitInit && *gitRepo != project.GitCommit {
+		log.Fatalf("unknown --git %q, use none, init or commit", *gitRepo)
+	}
+	if *tasksFile != project.TasksNone && *tasksFile != project.TasksMake && *tasksFile != project.TasksTaskfile {
+		log.Fatalf("unknown --tasks %q, use none, make or task", *tasksFile)
+	}
+	if *ciPipeline != project.CINone && *ciPipeline != project.CIGitHub && *ciPipeline != project.CIGitLab {
+		log.Fatalf("unknown --ci %q, use none, github or gitlab", *ciPipeline)
+	}
+	if *deployManifests != project.DeployNone && *deployManifests != project.DeployKustomize && *deployManifests != project.DeployHelm {
+		log.Fatalf("unknown --deploy %q, use none, kustomize or helm", *deployManifests)
+	}
+	if *loggingLibrary != project.LoggingStd && *loggingLibrary != project.LoggingSlog &&
+		*loggingLibrary != project.LoggingZap && *loggingLibrary != project.LoggingZerolog {
+		log.Fatalf("unknown --logging %q, use log, slog, zap or zerolog", *loggingLibrary)
+	}
+
+	var spec *scaffold.Config
+	switch {
+	case *specPath != "" && *preset != "":
+		log.Fatal("use either --spec or --preset")
+	case *specPath != "":
+		cfg, err := scaffold.LoadSpec(*specPath)
+		if err != nil {
+			log.Fatal(err)
+		}
+		spec = &cfg
+	case *preset != "":
+		cfg, err := scaffold.Preset(*preset)
+		if err != nil {
+			log.Fatal(err)
+		}
+		spec = &cfg
+	}
+	if *output == "json" && spec == nil {
+		log.Fatal("--output json needs --spec or --preset, there is no one to answer the questions")
+	}
+	if spec != nil && spec.Name != "" && len(args) == 0 {
+		strpath = spec.Name
+	}
+
+	depsCatalog, err = catalog.Load()
+	if err != nil {
+		log.Fatal(err)
+	}
+	hooks, err = scaffold.LoadUserHooks()
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	proj := project.NewConfiguration(strpath)
-	proj.Template = templates.LoadFullTemplates()
-	p := tea.NewProgram(projectName(proj))
+	proj.Root = root
+	proj.Offline = *offline
+	proj.SkipMissing = *skipMissing
+	if spec != nil {
+		applySpec(proj, *spec)
+	}
+	// flags win over the spec
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "git":
+			proj.Git = *gitRepo
+		case "tasks":
+			proj.Tasks = *tasksFile
+		case "lint":
+			proj.Lint = *lintConfig
+		case "ci":
+			proj.CI = *ciPipeline
+		case "deploy":
+			proj.Deploy = *deployManifests
+		case "logging":
+			proj.Logging = *loggingLibrary
+		case "telemetry":
+			proj.Telemetry = *telemetryInstrumentation
+		case "auth":
+			proj.Auth = *authMiddlewares
+		}
+	})
+	if *output == "json" {
+		err := generateJSON(scaffoldConfig(proj), scaffold.Options{
+			Root:        proj.Root,
+			Offline:     proj.Offline,
+			SkipMissing: proj.SkipMissing,
+		})
+		if err != nil {
+			os.Exit(exitCode(err))
+		}
+		return
+	}
+	first := projectName(proj)
+	if spec != nil {
+		// every choice was already made, they can still be changed from the summary
+		first = showSummary(proj, 0)
+	}
+	p := tea.NewProgram(first)
 
-	if err := p.Start(); err != nil {
+	final, err := p.StartReturningModel()
+	if err != nil {
 		log.Fatalf("Error: %v\n", err)
 	}
+	// the loader already showed why the generation failed
+	if loader, ok := final.(interface{ Err() error }); ok && loader.Err() != nil {
+		os.Exit(exitCode(loader.Err()))
+	}
+}
+
+// Exit codes of a failed generation, by class of failure
+const (
+	exitFailure    = 1
+	exitTemplate   = 3
+	exitFilesystem = 4
+	exitToolchain  = 5
+	exitHook       = 6
+)
+
+func exitCode(err error) int {
+	var stepErr *scaffold.StepError
+	if !errors.As(err, &stepErr) {
+		return exitFailure
+	}
+	switch stepErr.Class {
+	case scaffold.FailureTemplate:
+		return exitTemplate
+	case scaffold.FailureFilesystem:
+		return exitFilesystem
+	case scaffold.FailureToolchain:
+		return exitToolchain
+	case scaffold.FailureHook:
+		return exitHook
+	}
+	return exitFailure
+}
+
+// warm downloads the modules of a preset or spec file to the module cache, so it can be generated with --offline
+func warm(args []string) {
+	flags := flag.NewFlagSet("warm", flag.ExitOnError)
+	specPath := flags.String("spec", "", "JSON file with the project configuration")
+	flags.Parse(args)
+
+	var cfg scaffold.Config
+	var err error
+	switch {
+	case *specPath != "" && flags.NArg() > 0:
+		log.Fatal("use either --spec or a preset")
+	case *specPath != "":
+		cfg, err = scaffold.LoadSpec(*specPath)
+	case flags.NArg() > 0:
+		cfg, err = scaffold.Preset(flags.Arg(0))
+	default:
+		log.Fatal("usage: go-scaffold warm <preset> | go-scaffold warm --spec <file>")
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+	if cfg.Name == "" {
+		cfg.Name = "warm"
+	}
+
+	err = scaffold.Warm(context.Background(), cfg, scaffold.Options{
+		Progress: func(e scaffold.Event) {
+			switch e.Kind {
+			case scaffold.EventStepStarted:
+				fmt.Println(e.Step + "...")
+			case scaffold.EventDependencyFailed:
+				fmt.Printf("could not download %s: %v\n", e.Dependency, e.Err)
+			}
+		},
+	})
+	if err != nil {
+		log.Println(err)
+		os.Exit(exitCode(err))
+	}
+	fmt.Println("The module cache is ready, generate the project with --offline")
 }
@@ -0,0 +1,93 @@
+package scaffold
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/fedevilensky/go-scaffold/internal/project"
+	"github.com/fedevilensky/go-scaffold/internal/templates"
+)
+
+// PackManifest is the file at the root of a template pack that declares its hooks, it's not written to the project
+const PackManifest = templates.PackManifest
+
+// UserHooksFile is the path, relative to os.UserConfigDir, of the file with the hooks of the user
+const UserHooksFile = "go-scaffold/hooks.json"
+
+// Hook is a shell script run with sh -c in the project directory. Run is rendered with text/template
+// before, with the same data as the templates of the project, e.g. {{.Name}} is the module name
+type Hook struct {
+	// Name is shown in the progress, the first line of Run when empty
+	Name string `json:"name,omitempty"`
+	Run  string `json:"run"`
+	// Timeout is a duration such as 30s or 5m, the hook is killed once it passes. 2m when empty
+	Timeout string `json:"timeout,omitempty"`
+}
+
+// Hooks are declared by spec files, template pack manifests and the user hooks file
+type Hooks struct {
+	// PreHooks run once the folders of the project exist, before go mod init
+	PreHooks []Hook `json:"pre_hooks,omitempty"`
+	// PostHooks run once the project was generated, typically go mod tidy, git init or make setup
+	PostHooks []Hook `json:"post_hooks,omitempty"`
+}
+
+// LoadUserHooks reads the user hooks file, no hooks when there is none
+func LoadUserHooks() (Hooks, error) {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return Hooks{}, nil
+	}
+	name := filepath.Join(dir, filepath.FromSlash(UserHooksFile))
+	b, err := os.ReadFile(name)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Hooks{}, nil
+	}
+	if err != nil {
+		return Hooks{}, err
+	}
+	return parseHooks(name, b)
+}
+
+func packHooks(name string, pack fs.FS) (Hooks, error) {
+	b, err := fs.ReadFile(pack, PackManifest)
+	if errors.Is(err, fs.ErrNotExist) {
+		return Hooks{}, nil
+	}
+	if err != nil {
+		return Hooks{}, err
+	}
+	return parseHooks("manifest of template pack "+name, b)
+}
+
+func parseHooks(name string, b []byte) (Hooks, error) {
+	var hooks Hooks
+	if err := json.Unmarshal(b, &hooks); err != nil {
+		return Hooks{}, fmt.Errorf("parsing %s: %w", name, err)
+	}
+	return hooks, nil
+}
+
+func projectHooks(hooks []Hook) ([]project.Hook, error) {
+	converted := make([]project.Hook, 0, len(hooks))
+	for _, hook := range hooks {
+		if hook.Run == "" {
+			return nil, fmt.Errorf("hook %q has nothing to run", hook.Name)
+		}
+		var timeout time.Duration
+		if hook.Timeout != "" {
+			var err error
+			timeout, err = time.ParseDuration(hook.Timeout)
+			if err != nil {
+				return nil, fmt.Errorf("hook %q: invalid timeout: %w", hook.Name, err)
+			}
+		}
+		converted = append(converted, project.Hook{Name: hook.Name, Run: hook.Run, Timeout: timeout})
+	}
+	return converted, nil
+}
@@ -0,0 +1,319 @@
+// Package scaffold generates go projects, it is what the go-scaffold terminal UI runs,
+// and lets other tools generate the same projects without it
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+	"github.com/fedevilensky/go-scaffold/internal/project"
+	"github.com/fedevilensky/go-scaffold/internal/templates"
+)
+
+// Libraries and drivers a project can be generated with
+const (
+	WebLibraryGin        = project.WebLibraryGin
+	WebLibraryFiber      = project.WebLibraryFiber
+	WebLibraryGorillamux = project.WebLibraryGorillamux
+	WebLibraryHttp       = project.WebLibraryHttp
+	WebLibraryNone       = project.WebLibraryNone
+
+	DBLibraryGorm = project.DBLibraryGorm
+	DBLibrarySql  = project.DBLibrarySql
+	DBLibrarySqlx = project.DBLibrarySqlx
+	DBLibraryNone = project.DBLibraryNone
+
+	DBProviderGormMysql    = project.DBProviderGormMysql
+	DBProviderGormPostgres = project.DBProviderGormPostgres
+	DBProviderMysql        = project.DBProviderMysql
+	DBProviderPostgres     = project.DBProviderPostgres
+	DBProviderNone         = project.DBProviderNone
+
+	MocksInline = project.MocksInline
+	MocksFakes  = project.MocksFakes
+	MocksGomock = project.MocksGomock
+
+	GitNone   = project.GitNone
+	GitInit   = project.GitInit
+	GitCommit = project.GitCommit
+
+	TasksNone     = project.TasksNone
+	TasksMake     = project.TasksMake
+	TasksTaskfile = project.TasksTaskfile
+
+	CINone   = project.CINone
+	CIGitHub = project.CIGitHub
+	CIGitLab = project.CIGitLab
+
+	DeployNone      = project.DeployNone
+	DeployKustomize = project.DeployKustomize
+	DeployHelm      = project.DeployHelm
+
+	LoggingStd     = project.LoggingStd
+	LoggingSlog    = project.LoggingSlog
+	LoggingZap     = project.LoggingZap
+	LoggingZerolog = project.LoggingZerolog
+)
+
+type (
+	// Writer creates the files of the generated project
+	Writer = fsys.Writer
+	// CommandRunner runs the go commands (go mod init, go get, ...) needed to generate a project
+	CommandRunner = project.CommandRunner
+	// Command is a command run by a CommandRunner
+	Command = project.Command
+	// Event reports the progress of Generate
+	Event = project.Event
+	// EventKind tells what an Event reports
+	EventKind = project.EventKind
+)
+
+const (
+	EventStepStarted      = project.EventStepStarted
+	EventFileWritten      = project.EventFileWritten
+	EventCommandFinished  = project.EventCommandFinished
+	EventDependencyFailed = project.EventDependencyFailed
+	EventDone             = project.EventDone
+)
+
+// Config describes the project to generate, spec files and presets are Configs written in JSON
+type Config struct {
+	// Name is the module name
+	Name       string `json:"name,omitempty"`
+	WebLibrary string `json:"web_library,omitempty"`
+	DBLibrary  string `json:"db_library,omitempty"`
+	DBProvider string `json:"db_provider,omitempty"`
+	// Dependencies are added with go get, on top of the selected libraries. They map every module
+	// to the version or query it's resolved with, e.g. v1.9.1, latest or upgrade, go get chooses when empty.
+	// Selected libraries are pinned by adding them here
+	Dependencies map[string]string `json:"dependencies,omitempty"`
+	// Mocks is how generated tests mock interfaces, MocksInline when empty
+	Mocks string `json:"mocks,omitempty"`
+	// Vendor runs go mod vendor once the project is generated
+	Vendor bool `json:"vendor,omitempty"`
+	// Git creates a git repository with a .gitignore and a .gitattributes when GitInit, and commits
+	// the project to it when GitCommit, it's skipped when git is not installed. GitNone when empty
+	Git string `json:"git,omitempty"`
+	// Tasks generates a Makefile when TasksMake, or a Taskfile.yml when TasksTaskfile, with a compose.yaml for
+	// the database when there is one. TasksNone when empty
+	Tasks string `json:"tasks,omitempty"`
+	// Lint writes a .golangci.yml, an .editorconfig and a .pre-commit-config.yaml
+	Lint bool `json:"lint,omitempty"`
+	// CI generates a GitHub Actions workflow when CIGitHub, or a .gitlab-ci.yml when CIGitLab, that vets, lints
+	// and tests the project against a container of its DBMS, and builds its image. CINone when empty
+	CI string `json:"ci,omitempty"`
+	// Deploy generates Kubernetes manifests for the image of the Dockerfile, a kustomize base in
+	// deploy/kustomize/base when DeployKustomize or a Helm chart in deploy/helm when DeployHelm. DeployNone when empty
+	Deploy string `json:"deploy,omitempty"`
+	// Logging makes web projects log structured entries through pkg/logging, built on log/slog when LoggingSlog,
+	// zap when LoggingZap or zerolog when LoggingZerolog, with a request logger and request IDs. LoggingStd when empty
+	Logging string `json:"logging,omitempty"`
+	// Telemetry instruments the server and the database of full projects with OpenTelemetry, exporting traces and
+	// metrics with OTLP, or to stdout without a collector, and serving the metrics to Prometheus on /metrics
+	Telemetry bool `json:"telemetry,omitempty"`
+	// Auth generates pkg/auth and middlewares for web projects that authenticate requests with JWTs, signed with
+	// HS256 or RS256, or API keys, and add their principal to the request context
+	Auth bool `json:"auth,omitempty"`
+	// Packs are the names of registered template packs rendered after the built-in templates
+	Packs []string `json:"packs,omitempty"`
+	// Hooks run after the hooks of the packs
+	Hooks
+}
+
+// Options tell Generate where and how to generate the project
+type Options struct {
+	// Root is the directory the project is generated in, the working directory when empty
+	Root string
+	// FS is where files are written, the OS file system rooted at Root when nil
+	FS Writer
+	// Runner runs the go commands in Root, the OS one when nil
+	Runner CommandRunner
+	// Progress, when set, is called as the project is generated.
+	// It's called from the goroutine running Generate
+	Progress func(Event)
+	// Offline makes the go commands use the local module cache only. Generate fails with a
+	// *MissingModulesError before writing anything when selected modules are not in the cache,
+	// unless SkipMissing is set, in which case they are left out of the project
+	Offline     bool
+	SkipMissing bool
+}
+
+type (
+	// MissingModulesError lists the modules an offline Generate could not find in the module cache
+	MissingModulesError = project.MissingModulesError
+	// StepError is what Generate returns once it started generating the project, it tells which step failed
+	// and the class of the failure
+	StepError = project.StepError
+	// CommandError is a go command that failed, with its exit code and output
+	CommandError = project.CommandError
+)
+
+// Classes of StepError
+const (
+	FailureTemplate   = project.FailureTemplate
+	FailureFilesystem = project.FailureFilesystem
+	FailureToolchain  = project.FailureToolchain
+	FailureHook       = project.FailureHook
+)
+
+var (
+	packsMu sync.RWMutex
+	packs   = map[string]fs.FS{}
+)
+
+// RegisterPack makes a template pack available to Config.Packs.
+// Every file of the pack is written to the same path of the project: files ending in .tmpl are rendered
+// with text/template, without the suffix, and can use the templates defined by go-scaffold;
+// .tmpl files whose name starts with "_" only define templates; any other file is copied.
+// The PackManifest at the root of the pack, when there is one, declares the Hooks of the pack
+func RegisterPack(name string, pack fs.FS) error {
+	if name == "" {
+		return errors.New("template pack name is empty")
+	}
+	packsMu.Lock()
+	defer packsMu.Unlock()
+	if _, ok := packs[name]; ok {
+		return fmt.Errorf("template pack %q already registered", name)
+	}
+	packs[name] = pack
+	return nil
+}
+
+// Packs returns the names of the registered template packs, sorted
+func Packs() []string {
+	packsMu.RLock()
+	defer packsMu.RUnlock()
+	names := make([]string, 0, len(packs))
+	for name := range packs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// validate checks that every option of cfg is one of the values it accepts, empty ones keep their default
+func (cfg Config) validate() error {
+	options := []struct {
+		field   string
+		value   string
+		allowed []string
+	}{
+		{"web_library", cfg.WebLibrary, []string{WebLibraryGin, WebLibraryFiber, WebLibraryGorillamux, WebLibraryHttp}},
+		{"db_library", cfg.DBLibrary, []string{DBLibraryGorm, DBLibrarySql, DBLibrarySqlx}},
+		{"db_provider", cfg.DBProvider, []string{DBProviderGormMysql, DBProviderGormPostgres, DBProviderMysql, DBProviderPostgres}},
+		{"mocks", cfg.Mocks, []string{MocksInline, MocksFakes, MocksGomock}},
+		{"git", cfg.Git, []string{GitNone, GitInit, GitCommit}},
+		{"tasks", cfg.Tasks, []string{TasksNone, TasksMake, TasksTaskfile}},
+		{"ci", cfg.CI, []string{CINone, CIGitHub, CIGitLab}},
+		{"deploy", cfg.Deploy, []string{DeployNone, DeployKustomize, DeployHelm}},
+		{"logging", cfg.Logging, []string{LoggingStd, LoggingSlog, LoggingZap, LoggingZerolog}},
+	}
+	for _, option := range options {
+		if option.value != "" && !slices.Contains(option.allowed, option.value) {
+			last := len(option.allowed) - 1
+			return fmt.Errorf("unknown %s %q, use %s or %s",
+				option.field, option.value, strings.Join(option.allowed[:last], ", "), option.allowed[last])
+		}
+	}
+	return nil
+}
+
+// Generate generates the project described by cfg
+func Generate(ctx context.Context, cfg Config, opts Options) error {
+	return generate(ctx, cfg, opts, false)
+}
+
+// generate is Generate, warming also downloads the dependencies of the dependencies and skips the hooks of the packs
+func generate(ctx context.Context, cfg Config, opts Options, warming bool) error {
+	if cfg.Name == "" {
+		return errors.New("project name is empty")
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+
+	selected := make([]fs.FS, 0, len(cfg.Packs))
+	packsMu.RLock()
+	for _, name := range cfg.Packs {
+		pack, ok := packs[name]
+		if !ok {
+			packsMu.RUnlock()
+			return fmt.Errorf("template pack %q is not registered", name)
+		}
+		selected = append(selected, pack)
+	}
+	packsMu.RUnlock()
+
+	var hooks Hooks
+	// the project of a warm up is thrown away, the hooks of its packs have nothing to do there
+	if !warming {
+		for i, pack := range selected {
+			declared, err := packHooks(cfg.Packs[i], pack)
+			if err != nil {
+				return err
+			}
+			hooks.PreHooks = append(hooks.PreHooks, declared.PreHooks...)
+			hooks.PostHooks = append(hooks.PostHooks, declared.PostHooks...)
+		}
+	}
+	preHooks, err := projectHooks(append(hooks.PreHooks, cfg.PreHooks...))
+	if err != nil {
+		return err
+	}
+	postHooks, err := projectHooks(append(hooks.PostHooks, cfg.PostHooks...))
+	if err != nil {
+		return err
+	}
+
+	proj := project.NewConfiguration(cfg.Name)
+	proj.Name = cfg.Name
+	proj.WebLibrary = cfg.WebLibrary
+	proj.DBLibrary = cfg.DBLibrary
+	proj.DBProvider = cfg.DBProvider
+	proj.DoVendor = cfg.Vendor
+	for dep, version := range cfg.Dependencies {
+		proj.Dependencies[dep] = strings.TrimPrefix(version, "@")
+	}
+	if cfg.Mocks != "" {
+		proj.Mocks = cfg.Mocks
+	}
+	if cfg.Git != "" {
+		proj.Git = cfg.Git
+	}
+	if cfg.Tasks != "" {
+		proj.Tasks = cfg.Tasks
+	}
+	proj.Lint = cfg.Lint
+	proj.Telemetry = cfg.Telemetry
+	proj.Auth = cfg.Auth
+	if cfg.CI != "" {
+		proj.CI = cfg.CI
+	}
+	if cfg.Deploy != "" {
+		proj.Deploy = cfg.Deploy
+	}
+	if cfg.Logging != "" {
+		proj.Logging = cfg.Logging
+	}
+	if opts.Root != "" {
+		proj.Root = opts.Root
+	}
+	proj.FS = opts.FS
+	proj.Runner = opts.Runner
+	proj.OnEvent = opts.Progress
+	proj.Offline = opts.Offline
+	proj.SkipMissing = opts.SkipMissing
+	proj.DownloadAll = warming
+	proj.PreHooks = preHooks
+	proj.PostHooks = postHooks
+	proj.Template = templates.LoadFullTemplates(selected...)
+
+	return proj.Start(ctx)
+}
@@ -0,0 +1,319 @@
+package scaffold
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"os/exec"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/fedevilensky/go-scaffold/internal/fsys"
+)
+
+type nopRunner struct{}
+
+func (nopRunner) Run(context.Context, Command) ([]byte, error) {
+	return nil, nil
+}
+
+// registerTestPack registers pack for the duration of the test, so the test can run more than once
+func registerTestPack(t *testing.T, name string, pack fs.FS) {
+	t.Helper()
+
+	if err := RegisterPack(name, pack); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		packsMu.Lock()
+		defer packsMu.Unlock()
+		delete(packs, name)
+	})
+}
+
+func TestGenerateWithPack(t *testing.T) {
+	registerTestPack(t, "test", fstest.MapFS{
+		"README.md.tmpl":   {Data: []byte(`# {{.Name}}{{template "_note.tmpl" .}}`)},
+		"_note.tmpl":       {Data: []byte(` built with {{.WebLibrary}}`)},
+		"scripts/setup.sh": {Data: []byte("#!/bin/sh\n")},
+	})
+
+	files := fsys.Mem{}
+	var written []string
+	err := Generate(context.Background(), Config{
+		Name:       "example",
+		WebLibrary: WebLibraryGin,
+		Packs:      []string{"test"},
+	}, Options{
+		FS:     files,
+		Runner: nopRunner{},
+		Progress: func(e Event) {
+			if e.Kind == EventFileWritten {
+				written = append(written, e.Path)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := string(files["README.md"]); got != "# example built with "+WebLibraryGin {
+		t.Errorf("unexpected README.md: %q", got)
+	}
+	if got := string(files["scripts/setup.sh"]); got != "#!/bin/sh\n" {
+		t.Errorf("unexpected scripts/setup.sh: %q", got)
+	}
+	if _, ok := files["_note"]; ok {
+		t.Error("partial templates must not be written")
+	}
+	if _, ok := files["pkg/httphelpers/json.go"]; !ok {
+		t.Errorf("built-in templates were not rendered, got %s", strings.Join(written, ", "))
+	}
+	if len(written) != len(files) {
+		t.Errorf("expected an event for each of the %d files, got %d", len(files), len(written))
+	}
+}
+
+type recordingRunner struct {
+	cmds []string
+}
+
+func (r *recordingRunner) Run(_ context.Context, cmd Command) ([]byte, error) {
+	r.cmds = append(r.cmds, cmd.String())
+	return nil, nil
+}
+
+func TestGenerateRunsHooks(t *testing.T) {
+	registerTestPack(t, "hooked", fstest.MapFS{
+		PackManifest: {Data: []byte(`{"post_hooks": [{"name": "tidy", "run": "go mod tidy"}]}`)},
+		"Makefile":   {Data: []byte("setup:\n")},
+	})
+
+	files := fsys.Mem{}
+	runner := &recordingRunner{}
+	cfg := Config{Name: "example", Packs: []string{"hooked"}}
+	cfg.PreHooks = []Hook{{Run: "echo {{.Name}}"}}
+	cfg.PostHooks = []Hook{{Run: "make setup", Timeout: "1m"}}
+	err := Generate(context.Background(), cfg, Options{FS: files, Runner: runner})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if _, ok := files[PackManifest]; ok {
+		t.Error("the manifest must not be written to the project")
+	}
+	var hooks []string
+	for _, cmd := range runner.cmds {
+		if strings.HasPrefix(cmd, "sh -c ") {
+			hooks = append(hooks, strings.TrimPrefix(cmd, "sh -c "))
+		}
+	}
+	// the hooks of the packs run first
+	want := []string{"echo example", "go mod tidy", "make setup"}
+	if strings.Join(hooks, "|") != strings.Join(want, "|") {
+		t.Errorf("expected hooks %q, got %q", want, hooks)
+	}
+
+	cfg.PostHooks = []Hook{{Run: "make setup", Timeout: "soon"}}
+	if err := Generate(context.Background(), cfg, Options{FS: fsys.Mem{}, Runner: runner}); err == nil {
+		t.Error("expected an error for an invalid timeout")
+	}
+}
+
+func TestGenerateUnknownPack(t *testing.T) {
+	err := Generate(context.Background(), Config{Name: "example", Packs: []string{"missing"}}, Options{
+		FS:     fsys.Mem{},
+		Runner: nopRunner{},
+	})
+	if err == nil {
+		t.Fatal("expected an error for an unregistered pack")
+	}
+}
+
+func TestGenerateUnknownOption(t *testing.T) {
+	err := Generate(context.Background(), Config{Name: "example", CI: "jenkins"}, Options{
+		FS:     fsys.Mem{},
+		Runner: nopRunner{},
+	})
+	if err == nil || !strings.Contains(err.Error(), `unknown ci "jenkins", use none, github or gitlab`) {
+		t.Fatalf("expected an error naming the option and its values, got %v", err)
+	}
+}
+
+func TestParseSpecUnknownOption(t *testing.T) {
+	tests := []struct {
+		spec string
+		want string
+	}{
+		{`{"web_library": "echo"}`, `unknown web_library "echo"`},
+		{`{"db_library": "ent"}`, `unknown db_library "ent"`},
+		{`{"db_provider": "sqlite"}`, `unknown db_provider "sqlite"`},
+		{`{"mocks": "mockery"}`, `unknown mocks "mockery", use inline, fakes or gomock`},
+		{`{"git": "push"}`, `unknown git "push", use none, init or commit`},
+		{`{"tasks": "just"}`, `unknown tasks "just", use none, make or task`},
+		{`{"deploy": "nomad"}`, `unknown deploy "nomad", use none, kustomize or helm`},
+		{`{"logging": "logrus"}`, `unknown logging "logrus", use log, slog, zap or zerolog`},
+	}
+	for _, tt := range tests {
+		_, err := parseSpec("spec.json", []byte(tt.spec))
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%s: expected an error containing %q, got %v", tt.spec, tt.want, err)
+		}
+	}
+
+	if _, err := parseSpec("spec.json", []byte(`{"web_library": "net/http", "ci": "github"}`)); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+// readOnlyFS fails every file it's asked to create
+type readOnlyFS struct {
+	fsys.Mem
+}
+
+func (readOnlyFS) Create(name string) (io.WriteCloser, error) {
+	return nil, errors.New("read-only file system")
+}
+
+func TestGenerateFailureClasses(t *testing.T) {
+	registerTestPack(t, "broken", fstest.MapFS{
+		"README.md.tmpl": {Data: []byte(`# {{.Missing}}`)},
+	})
+
+	tests := []struct {
+		name  string
+		cfg   Config
+		opts  Options
+		step  string
+		class string
+	}{
+		{
+			name:  "template",
+			cfg:   Config{Name: "example", WebLibrary: WebLibraryGin, Packs: []string{"broken"}},
+			opts:  Options{FS: fsys.Mem{}, Runner: nopRunner{}},
+			step:  "Creating helper methods and middlewares in pkg/",
+			class: FailureTemplate,
+		},
+		{
+			name:  "filesystem",
+			cfg:   Config{Name: "example", WebLibrary: WebLibraryGin},
+			opts:  Options{FS: readOnlyFS{fsys.Mem{}}, Runner: nopRunner{}},
+			step:  "Creating helper methods and middlewares in pkg/",
+			class: FailureFilesystem,
+		},
+		{
+			name:  "toolchain",
+			cfg:   Config{Name: "example", WebLibrary: WebLibraryGin},
+			opts:  Options{FS: fsys.Mem{}, Runner: failingRunner{}},
+			step:  "Initializing mod",
+			class: FailureToolchain,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var done Event
+			tt.opts.Progress = func(e Event) {
+				if e.Kind == EventDone {
+					done = e
+				}
+			}
+			err := Generate(context.Background(), tt.cfg, tt.opts)
+			var stepErr *StepError
+			if !errors.As(err, &stepErr) {
+				t.Fatalf("expected a StepError, got %v", err)
+			}
+			if stepErr.Step != tt.step || stepErr.Class != tt.class {
+				t.Errorf("expected %s to fail with a %s error, got %s with a %s error: %v",
+					tt.step, tt.class, stepErr.Step, stepErr.Class, err)
+			}
+			if done.Err != err {
+				t.Errorf("expected the done event to carry the error, got %v", done.Err)
+			}
+		})
+	}
+}
+
+type failingRunner struct{}
+
+func (failingRunner) Run(context.Context, Command) ([]byte, error) {
+	return []byte("go: not found\n"), errors.New("exit status 1")
+}
+
+// downloadFailingRunner fails go mod download all with the exit code of a real command
+type downloadFailingRunner struct{}
+
+func (downloadFailingRunner) Run(_ context.Context, cmd Command) ([]byte, error) {
+	if cmd.String() != "go mod download all" {
+		return nil, nil
+	}
+	return []byte("go: network unreachable\n"), exec.Command("sh", "-c", "exit 3").Run()
+}
+
+func TestWarmDownloadFailure(t *testing.T) {
+	var finished []string
+	err := Warm(context.Background(), Config{Name: "example", WebLibrary: WebLibraryGin}, Options{
+		Runner: downloadFailingRunner{},
+		Progress: func(e Event) {
+			if e.Kind == EventCommandFinished {
+				finished = append(finished, e.Command.String())
+			}
+		},
+	})
+	var stepErr *StepError
+	if !errors.As(err, &stepErr) || stepErr.Step != "Downloading the dependencies of the dependencies" {
+		t.Fatalf("expected the download step to fail, got %v", err)
+	}
+	var cmdErr *CommandError
+	if !errors.As(err, &cmdErr) || cmdErr.ExitCode != 3 {
+		t.Fatalf("expected a CommandError with exit code 3, got %v", err)
+	}
+	if !slices.Contains(finished, "go mod download all") {
+		t.Errorf("expected go mod download all to be reported, got %q", finished)
+	}
+}
+
+func TestWarmSkipsHooks(t *testing.T) {
+	registerTestPack(t, "warmed", fstest.MapFS{
+		PackManifest: {Data: []byte(`{"pre_hooks": [{"run": "echo pre"}], "post_hooks": [{"run": "make setup"}]}`)},
+	})
+
+	runner := &recordingRunner{}
+	cfg := Config{Name: "example", WebLibrary: WebLibraryGin, Git: GitCommit, Packs: []string{"warmed"}}
+	cfg.PostHooks = []Hook{{Run: "go mod tidy"}}
+	if err := Warm(context.Background(), cfg, Options{Runner: runner}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for _, cmd := range runner.cmds {
+		if strings.HasPrefix(cmd, "sh -c ") || strings.HasPrefix(cmd, "git ") {
+			t.Errorf("expected warming not to run %q", cmd)
+		}
+	}
+	if !slices.Contains(runner.cmds, "go mod download all") {
+		t.Errorf("expected go mod download all, got %q", runner.cmds)
+	}
+}
+
+func TestPresets(t *testing.T) {
+	names := Presets()
+	if len(names) == 0 {
+		t.Fatal("no presets embedded")
+	}
+	for _, name := range names {
+		cfg, err := Preset(name)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for _, lib := range []string{cfg.WebLibrary, cfg.DBLibrary, cfg.DBProvider} {
+			if _, pinned := cfg.Dependencies[lib]; !pinned && strings.Contains(lib, ".") {
+				t.Errorf("%s: %s is not pinned", name, lib)
+			}
+		}
+	}
+	if _, err := Preset("missing"); err == nil {
+		t.Error("expected an error for an unknown preset")
+	}
+}
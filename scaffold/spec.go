@@ -0,0 +1,58 @@
+package scaffold
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"sort"
+	"strings"
+)
+
+//go:embed presets
+var presets embed.FS
+
+// LoadSpec reads the Config written as JSON in the file at name
+func LoadSpec(name string) (Config, error) {
+	b, err := os.ReadFile(name)
+	if err != nil {
+		return Config{}, err
+	}
+	return parseSpec(name, b)
+}
+
+// Preset returns the Config of the preset called name
+func Preset(name string) (Config, error) {
+	b, err := fs.ReadFile(presets, path.Join("presets", name+".json"))
+	if err != nil {
+		return Config{}, fmt.Errorf("unknown preset %q, available presets: %s", name, strings.Join(Presets(), ", "))
+	}
+	return parseSpec(name, b)
+}
+
+// Presets returns the names of the embedded presets, sorted
+func Presets() []string {
+	entries, err := presets.ReadDir("presets")
+	if err != nil {
+		return nil
+	}
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+func parseSpec(name string, b []byte) (Config, error) {
+	var cfg Config
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return Config{}, fmt.Errorf("parsing spec %s: %w", name, err)
+	}
+	if err := cfg.validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid spec %s: %w", name, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,51 @@
+package scaffold
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Warm downloads every module the project described by cfg needs into the local module cache,
+// so the same project can be generated later with Options.Offline. The project is generated in a
+// temporary directory that is removed afterwards, without running its hooks or the hooks of its packs, and
+// without git, tasks, lint, CI or deployment files. opts.Root, opts.FS and opts.Offline are ignored
+func Warm(ctx context.Context, cfg Config, opts Options) error {
+	dir, err := os.MkdirTemp("", "go-scaffold-warm-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	var failed []string
+	progress := opts.Progress
+	// only what adds modules to the cache is kept
+	cfg.Vendor = false
+	cfg.Hooks = Hooks{}
+	cfg.Git = GitNone
+	cfg.Tasks = TasksNone
+	cfg.Lint = false
+	cfg.CI = CINone
+	cfg.Deploy = DeployNone
+	opts = Options{
+		Root:   dir,
+		Runner: opts.Runner,
+		Progress: func(e Event) {
+			if e.Kind == EventDependencyFailed {
+				failed = append(failed, e.Dependency)
+			}
+			if progress != nil {
+				progress(e)
+			}
+		},
+	}
+	if err := generate(ctx, cfg, opts, true); err != nil {
+		return err
+	}
+	if len(failed) > 0 {
+		return &StepError{Step: "Installing dependencies", Class: FailureToolchain,
+			Err: fmt.Errorf("could not download %s", strings.Join(failed, ", "))}
+	}
+	return nil
+}
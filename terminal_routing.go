@@ -1,16 +1,19 @@
 package main
 
 import (
+	"context"
 	"errors"
-	"log"
+	"sort"
 	"strconv"
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
 
+	"github.com/fedevilensky/go-scaffold/internal/catalog"
 	"github.com/fedevilensky/go-scaffold/internal/inputmodels"
 	"github.com/fedevilensky/go-scaffold/internal/progressloader"
 	"github.com/fedevilensky/go-scaffold/internal/project"
+	"github.com/fedevilensky/go-scaffold/scaffold"
 )
 
 const (
@@ -20,9 +23,27 @@ const (
 	removeDep = "removeDep"
 	addDep    = "addDep"
 	vendor    = "vendor"
+	gitRepo   = "gitRepo"
+	tasks     = "tasks"
+	lint      = "lint"
+	ci        = "ci"
+	deploy    = "deploy"
+	logging   = "logging"
+	telemetry = "telemetry"
+	auth      = "auth"
+	mocks     = "mocks"
 	build     = "build"
 )
 
+// depsCatalog is the catalog of dependencies offered by the wizard, main loads it
+var depsCatalog *catalog.Catalog
+
+// hooks run when the project is built, main loads the ones of the user and a spec file can add more
+var hooks scaffold.Hooks
+
+// packs are the registered template packs a spec file asks for, rendered when the project is built
+var packs []string
+
 func projectName(proj *project.Configuration) tea.Model {
 	next := func() tea.Model { return selectWebLibrary(proj) }
 	return projectNameWithNext(proj, next)
@@ -159,33 +180,48 @@ func selectGormDBProviderWithNext(proj *project.Configuration, next func() tea.M
 }
 
 func commonPackages(proj *project.Configuration) tea.Model {
-	next := func() tea.Model { return otherPackages(proj) }
+	next := func() tea.Model { return selectMocks(proj) }
 	return commonPackagesWithNext(proj, next)
 }
 
 func commonPackagesWithNext(proj *project.Configuration, next func() tea.Model) tea.Model {
+	entries := depsCatalog.Grouped()
+	choices := make([]string, len(entries))
+	values := make([]string, len(entries))
+	groups := make([]string, len(entries))
+	for i, entry := range entries {
+		choices[i] = entry.Name + ": " + entry.Description
+		values[i] = entry.Module
+		groups[i] = entry.Category
+	}
+
+	selectedModules := func(selected []inputmodels.Selection) []string {
+		modules := []string{}
+		for _, sel := range selected {
+			if sel.IsSelected {
+				modules = append(modules, sel.Value())
+			}
+		}
+		return modules
+	}
+
 	opts := inputmodels.ChoiceModelOptions{
-		Choices: []string{
-			"Use spf13/cobra",
-			"Use redis",
-			"Use cleanenv",
-			"Use MongoDB",
-			"Use AMQP",
-			"Use stretchr/testify",
-		},
-		Values: []string{
-			"github.com/spf13/cobra",
-			"github.com/go-redis/redis",
-			"github.com/ilyakaznacheev/cleanenv",
-			"github.com/mongodb/mongo-go-driver/mongo",
-			"github.com/rabbitmq/amqp091-go",
-			"github/com/stretchr/testify",
+		Choices: choices,
+		Values:  values,
+		Groups:  groups,
+		Header:  "Choose between common packages",
+		Validate: func(selected []inputmodels.Selection) error {
+			modules := selectedModules(selected)
+			for dep := range proj.Dependencies {
+				modules = append(modules, dep)
+			}
+			return depsCatalog.CheckConflicts(modules)
 		},
-		Header: "Choose between common packages",
 		OnEnter: func(selected []inputmodels.Selection) error {
-			for _, sel := range selected {
-				if sel.IsSelected {
-					proj.Dependencies[sel.Value()] = struct{}{}
+			for _, module := range selectedModules(selected) {
+				if _, ok := proj.Dependencies[module]; !ok {
+					entry, _ := depsCatalog.Lookup(module)
+					proj.Dependencies[module] = entry.Version
 				}
 			}
 			return nil
@@ -195,6 +231,33 @@ func commonPackagesWithNext(proj *project.Configuration, next func() tea.Model)
 	return inputmodels.NewchoiceModel(opts)
 }
 
+func selectMocks(proj *project.Configuration) tea.Model {
+	next := func() tea.Model { return otherPackages(proj) }
+	// without a DB library there is no logic layer, so there is nothing to mock
+	if proj.DBLibrary == project.DBLibraryNone {
+		return next()
+	}
+	return selectMocksWithNext(proj, next)
+}
+
+func selectMocksWithNext(proj *project.Configuration, next func() tea.Model) tea.Model {
+	opts := inputmodels.RadioSelectOptions{
+		Header: "How do you want to mock interfaces in tests?",
+		Choices: []string{
+			"Inline fakes declared in each test",
+			"Hand-written fakes in internal/helloworld/mocks",
+			"gomock (go.uber.org/mock), regenerated with go generate",
+		},
+		Values: []string{project.MocksInline, project.MocksFakes, project.MocksGomock},
+		OnEnter: func(selection string, _ int) error {
+			proj.Mocks = selection
+			return nil
+		},
+		Next: nextFunc(next),
+	}
+	return inputmodels.NewRadioSelect(opts)
+}
+
 func otherPackages(proj *project.Configuration) tea.Model {
 	next := func() tea.Model { return selectVendoring(proj) }
 	return otherPackagesWithNext(proj, next)
@@ -202,10 +265,11 @@ func otherPackages(proj *project.Configuration) tea.Model {
 
 func otherPackagesWithNext(proj *project.Configuration, next func() tea.Model) tea.Model {
 	opts := inputmodels.TextInputOptions{
-		Header:      "Enter other packages you want to use",
+		Header:      "Enter other packages you want to use, optionally with a version: module@v1.2.3, module@latest",
 		Placeholder: "Leave empty if you don't want to use any other packages",
 		OnEnter: func(input string) error {
-			proj.Dependencies[input] = struct{}{}
+			module, version := project.ParseDependency(input)
+			proj.Dependencies[module] = version
 			return nil
 		},
 		Next: func() (tea.Model, tea.Cmd) {
@@ -220,7 +284,7 @@ func otherPackagesWithNext(proj *project.Configuration, next func() tea.Model) t
 }
 
 func selectVendoring(proj *project.Configuration) tea.Model {
-	next := func() tea.Model { return showSummary(proj, 0) }
+	next := func() tea.Model { return selectGit(proj) }
 	return selectVendorigWithNext(proj, next)
 }
 
@@ -245,6 +309,58 @@ func selectVendorigWithNext(proj *project.Configuration, next func() tea.Model)
 	return inputmodels.NewRadioSelect(opts)
 }
 
+func selectGit(proj *project.Configuration) tea.Model {
+	next := func() tea.Model { return selectCI(proj) }
+	return selectGitWithNext(proj, next)
+}
+
+func selectGitWithNext(proj *project.Configuration, next func() tea.Model) tea.Model {
+	opts := inputmodels.RadioSelectOptions{
+		Header: "Do you want a git repository for the project?",
+		Choices: []string{
+			gitDescriptions[project.GitNone],
+			gitDescriptions[project.GitInit],
+			gitDescriptions[project.GitCommit],
+		},
+		Values: []string{project.GitNone, project.GitInit, project.GitCommit},
+		OnEnter: func(selection string, _ int) error {
+			proj.Git = selection
+			return nil
+		},
+		Next: nextFunc(next),
+	}
+	return inputmodels.NewRadioSelect(opts)
+}
+
+func selectCI(proj *project.Configuration) tea.Model {
+	next := func() tea.Model { return showSummary(proj, 0) }
+	return selectCIWithNext(proj, next)
+}
+
+func selectCIWithNext(proj *project.Configuration, next func() tea.Model) tea.Model {
+	opts := inputmodels.RadioSelectOptions{
+		Header: "Do you want a CI pipeline that vets, lints and tests the project, and builds its image?",
+		Choices: []string{
+			ciDescriptions[project.CINone],
+			ciDescriptions[project.CIGitHub],
+			ciDescriptions[project.CIGitLab],
+		},
+		Values: []string{project.CINone, project.CIGitHub, project.CIGitLab},
+		OnEnter: func(selection string, _ int) error {
+			proj.CI = selection
+			return nil
+		},
+		Next: nextFunc(next),
+	}
+	return inputmodels.NewRadioSelect(opts)
+}
+
+var ciDescriptions = map[string]string{
+	project.CINone:   "none",
+	project.CIGitHub: "GitHub Actions, in .github/workflows/ci.yml",
+	project.CIGitLab: "GitLab CI, in .gitlab-ci.yml",
+}
+
 func showSummary(proj *project.Configuration, cursorPosition int) tea.Model {
 	var next func() tea.Model
 	choices, values := buildChoicesAndValues(proj)
@@ -280,19 +396,64 @@ func showSummary(proj *project.Configuration, cursorPosition int) tea.Model {
 				next = func() tea.Model {
 					return otherPackagesWithNext(proj, func() tea.Model { return showSummary(proj, cursorPosition) })
 				}
+			case input == mocks:
+				next = func() tea.Model {
+					return selectMocksWithNext(proj, func() tea.Model { return showSummary(proj, cursorPosition) })
+				}
 			case input == vendor:
 				proj.DoVendor = !proj.DoVendor
 				next = func() tea.Model {
 					return showSummary(proj, cursorPosition)
 				}
+			case input == gitRepo:
+				next = func() tea.Model {
+					return selectGitWithNext(proj, func() tea.Model { return showSummary(proj, cursorPosition) })
+				}
+			case input == tasks:
+				proj.Tasks = nextTasksOption(proj.Tasks)
+				next = func() tea.Model {
+					return showSummary(proj, cursorPosition)
+				}
+			case input == lint:
+				proj.Lint = !proj.Lint
+				next = func() tea.Model {
+					return showSummary(proj, cursorPosition)
+				}
+			case input == deploy:
+				proj.Deploy = nextDeployOption(proj.Deploy)
+				next = func() tea.Model {
+					return showSummary(proj, cursorPosition)
+				}
+			case input == logging:
+				proj.Logging = nextLoggingOption(proj.Logging)
+				next = func() tea.Model {
+					return showSummary(proj, cursorPosition)
+				}
+			case input == telemetry:
+				proj.Telemetry = !proj.Telemetry
+				next = func() tea.Model {
+					return showSummary(proj, cursorPosition)
+				}
+			case input == auth:
+				proj.Auth = !proj.Auth
+				next = func() tea.Model {
+					return showSummary(proj, cursorPosition)
+				}
+			case input == ci:
+				next = func() tea.Model {
+					return selectCIWithNext(proj, func() tea.Model { return showSummary(proj, cursorPosition) })
+				}
 			case input == build:
-				go func() {
-					err := proj.Start()
-					if err != nil {
-						log.Fatal(err)
-					}
-				}()
-				next = func() tea.Model { return buildingBoilerplate(proj) }
+				status := &progressloader.Status{}
+				generate := func() error {
+					return scaffold.Generate(context.Background(), scaffoldConfig(proj), scaffold.Options{
+						Root:        proj.Root,
+						Progress:    status.Update,
+						Offline:     proj.Offline,
+						SkipMissing: proj.SkipMissing,
+					})
+				}
+				next = func() tea.Model { return buildingBoilerplate(status, generate) }
 			default:
 				return errors.New("unexpected input")
 			}
@@ -329,26 +490,192 @@ func buildChoicesAndValues(proj *project.Configuration) ([]string, []string) {
 	}
 	values = append(values, db)
 
-	if len(proj.Dependencies) > 0 {
-		for dep := range proj.Dependencies {
-			choices = append(choices, "Remove dependency: "+dep)
-			values = append(values, removeDep+dep)
-		}
+	deps := make([]string, 0, len(proj.Dependencies))
+	for dep := range proj.Dependencies {
+		deps = append(deps, dep)
+	}
+	sort.Strings(deps)
+	for _, dep := range deps {
+		choices = append(choices, "Remove dependency: "+project.FormatDependency(dep, proj.Dependencies[dep]))
+		values = append(values, removeDep+dep)
 	}
 	choices = append(choices, "Add dependency")
 	values = append(values, addDep)
 
+	if proj.DBLibrary != project.DBLibraryNone {
+		choices = append(choices, "Mocks: "+proj.Mocks)
+		values = append(values, mocks)
+	}
+
 	choices = append(choices, "Vendoring: "+strconv.FormatBool(proj.DoVendor))
 	values = append(values, vendor)
 
+	choices = append(choices, "Git repository: "+gitDescriptions[proj.Git])
+	values = append(values, gitRepo)
+
+	choices = append(choices, "Tasks: "+tasksDescriptions[proj.Tasks])
+	values = append(values, tasks)
+
+	choices = append(choices, "Lint configuration (golangci-lint, editorconfig, pre-commit): "+strconv.FormatBool(proj.Lint))
+	values = append(values, lint)
+
+	choices = append(choices, "CI pipeline: "+ciDescriptions[proj.CI])
+	values = append(values, ci)
+
+	// the manifests deploy the image of the Dockerfile, which only web projects have
+	if proj.WebLibrary != project.WebLibraryNone {
+		choices = append(choices, "Deployment: "+deployDescriptions[proj.Deploy])
+		values = append(values, deploy)
+	}
+
+	// the request logger is the reason to choose a library, so the choice is only offered to web projects
+	if proj.WebLibrary != project.WebLibraryNone {
+		choices = append(choices, "Logging: "+loggingDescriptions[proj.Logging])
+		values = append(values, logging)
+	}
+
+	// only full projects have a server and a database to instrument
+	if proj.WebLibrary != project.WebLibraryNone && proj.DBLibrary != project.DBLibraryNone {
+		choices = append(choices, "Telemetry (OpenTelemetry traces and metrics, Prometheus /metrics): "+strconv.FormatBool(proj.Telemetry))
+		values = append(values, telemetry)
+	}
+
+	// the middlewares authenticate requests, which only web projects have
+	if proj.WebLibrary != project.WebLibraryNone {
+		choices = append(choices, "Auth middlewares (JWT, API keys): "+strconv.FormatBool(proj.Auth))
+		values = append(values, auth)
+	}
+
 	choices = append(choices, "Build")
 	values = append(values, build)
 
 	return choices, values
 }
 
-func buildingBoilerplate(proj *project.Configuration) tea.Model {
-	return progressloader.NewLoader(proj)
+var gitDescriptions = map[string]string{
+	project.GitNone:   "none",
+	project.GitInit:   "git init, with .gitignore and .gitattributes",
+	project.GitCommit: "git init, with .gitignore and .gitattributes, and an initial commit",
+}
+
+var tasksDescriptions = map[string]string{
+	project.TasksNone:     "none",
+	project.TasksMake:     "Makefile",
+	project.TasksTaskfile: "Taskfile.yml",
+}
+
+// nextTasksOption cycles through the task runners every time the summary entry is selected
+func nextTasksOption(current string) string {
+	switch current {
+	case project.TasksNone:
+		return project.TasksMake
+	case project.TasksMake:
+		return project.TasksTaskfile
+	}
+	return project.TasksNone
+}
+
+var deployDescriptions = map[string]string{
+	project.DeployNone:      "none",
+	project.DeployKustomize: "kustomize base in deploy/kustomize/base",
+	project.DeployHelm:      "Helm chart in deploy/helm",
+}
+
+// nextDeployOption cycles through the deployment manifests every time the summary entry is selected
+func nextDeployOption(current string) string {
+	switch current {
+	case project.DeployNone:
+		return project.DeployKustomize
+	case project.DeployKustomize:
+		return project.DeployHelm
+	}
+	return project.DeployNone
+}
+
+var loggingDescriptions = map[string]string{
+	project.LoggingStd:     "standard log package",
+	project.LoggingSlog:    "log/slog, with request IDs",
+	project.LoggingZap:     "zap, with request IDs",
+	project.LoggingZerolog: "zerolog, with request IDs",
+}
+
+// nextLoggingOption cycles through the logging libraries every time the summary entry is selected
+func nextLoggingOption(current string) string {
+	switch current {
+	case project.LoggingStd:
+		return project.LoggingSlog
+	case project.LoggingSlog:
+		return project.LoggingZap
+	case project.LoggingZap:
+		return project.LoggingZerolog
+	}
+	return project.LoggingStd
+}
+
+func buildingBoilerplate(status *progressloader.Status, generate func() error) tea.Model {
+	return progressloader.NewLoader(status, generate)
+}
+
+// scaffoldConfig turns the choices made in the wizard into what scaffold.Generate expects
+func scaffoldConfig(proj *project.Configuration) scaffold.Config {
+	deps := make(map[string]string, len(proj.Dependencies))
+	for dep, version := range proj.Dependencies {
+		deps[dep] = version
+	}
+	return scaffold.Config{
+		Name:         proj.Name,
+		WebLibrary:   proj.WebLibrary,
+		DBLibrary:    proj.DBLibrary,
+		DBProvider:   proj.DBProvider,
+		Dependencies: deps,
+		Mocks:        proj.Mocks,
+		Vendor:       proj.DoVendor,
+		Git:          proj.Git,
+		Tasks:        proj.Tasks,
+		Lint:         proj.Lint,
+		CI:           proj.CI,
+		Deploy:       proj.Deploy,
+		Logging:      proj.Logging,
+		Telemetry:    proj.Telemetry,
+		Auth:         proj.Auth,
+		Packs:        packs,
+		Hooks:        hooks,
+	}
+}
+
+// applySpec makes the choices of a spec file or preset, as if they were made in the wizard
+func applySpec(proj *project.Configuration, spec scaffold.Config) {
+	proj.WebLibrary = spec.WebLibrary
+	proj.DBLibrary = spec.DBLibrary
+	proj.DBProvider = spec.DBProvider
+	proj.DoVendor = spec.Vendor
+	proj.Lint = spec.Lint
+	proj.Telemetry = spec.Telemetry
+	proj.Auth = spec.Auth
+	if spec.CI != "" {
+		proj.CI = spec.CI
+	}
+	if spec.Deploy != "" {
+		proj.Deploy = spec.Deploy
+	}
+	if spec.Logging != "" {
+		proj.Logging = spec.Logging
+	}
+	if spec.Mocks != "" {
+		proj.Mocks = spec.Mocks
+	}
+	if spec.Git != "" {
+		proj.Git = spec.Git
+	}
+	if spec.Tasks != "" {
+		proj.Tasks = spec.Tasks
+	}
+	for dep, version := range spec.Dependencies {
+		proj.Dependencies[dep] = strings.TrimPrefix(version, "@")
+	}
+	packs = append(packs, spec.Packs...)
+	hooks.PreHooks = append(hooks.PreHooks, spec.PreHooks...)
+	hooks.PostHooks = append(hooks.PostHooks, spec.PostHooks...)
 }
 
 func nextFunc(next func() tea.Model) func() (tea.Model, tea.Cmd) {